@@ -1,6 +1,9 @@
 package main
 
-import "fmt"
+import (
+	"flag"
+	"fmt"
+)
 
 type command struct {
 	name string
@@ -27,3 +30,21 @@ func (c *commands) register(name string, f func(s *state, cmd command) error) er
 	c.cmd_map[name] = f
 	return nil
 }
+
+// parseFlags parses the flags of a command wherever they appear among its
+// arguments (the flag package stops at the first positional argument) and
+// returns the positional arguments in order
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		err := flags.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ercorn/gator/internal/feed"
+	"github.com/ercorn/gator/internal/htmltext"
+)
+
+// page is a fetched document that may be either a feed or a web page
+type page struct {
+	URL         *url.URL
+	ContentType string
+	Body        []byte
+}
+
+func (f *fetcher) fetchPage(ctx context.Context, pageUrl string) (*page, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "gator")
+
+	res, err := f.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	body, err := f.readBody(res)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request: %w", err)
+	}
+	return &page{
+		URL:         res.Request.URL,
+		ContentType: res.Header.Get("Content-Type"),
+		Body:        body,
+	}, nil
+}
+
+// discoverFeed turns the URL given to addfeed into a feed URL. Anything that
+// isn't a web page is taken to be the feed itself. For a web page, the feeds
+// it links to are used, or the usual feed locations on the site when it
+// doesn't link to any; the user picks one when there are several.
+func discoverFeed(ctx context.Context, s *state, rawUrl string, in io.Reader, out io.Writer) (string, error) {
+	p, err := s.fetcher.fetchPage(ctx, rawUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", rawUrl, err)
+	}
+	if !feed.IsHTML(p.ContentType, p.Body) {
+		return rawUrl, nil
+	}
+
+	candidates, err := feed.DiscoverLinks(p.URL, p.ContentType, p.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", rawUrl, err)
+	}
+	if len(candidates) == 0 {
+		candidates = probeFeedPaths(ctx, s, p.URL)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no feed found at %s", rawUrl)
+	case 1:
+		fmt.Fprintf(out, "Found feed %s\n", htmltext.StripControl(candidates[0].URL))
+		return candidates[0].URL, nil
+	}
+	return chooseFeed(candidates, rawUrl, in, out)
+}
+
+// probeFeedPaths tries the common feed locations on the site and returns the
+// first one that serves a feed we can parse
+func probeFeedPaths(ctx context.Context, s *state, site *url.URL) []feed.Link {
+	found := []feed.Link{}
+	for _, path := range feed.CommonFeedPaths {
+		candidate := site.ResolveReference(&url.URL{Path: path})
+		p, err := s.fetcher.fetchPage(ctx, candidate.String())
+		if err != nil || feed.IsHTML(p.ContentType, p.Body) {
+			continue
+		}
+		parsed, err := feed.Parse(p.ContentType, p.Body)
+		if err != nil {
+			continue
+		}
+		found = append(found, feed.Link{
+			URL:   candidate.String(),
+			Type:  p.ContentType,
+			Title: parsed.Title,
+		})
+		//sites tend to serve the same feed under several of these paths
+		break
+	}
+	return found
+}
+
+func chooseFeed(candidates []feed.Link, rawUrl string, in io.Reader, out io.Writer) (string, error) {
+	fmt.Fprintf(out, "Found %d feeds at %s:\n", len(candidates), rawUrl)
+	for i, c := range candidates {
+		//titles and urls come from the page, print them without escapes
+		title := htmltext.StripControl(c.Title)
+		if title == "" {
+			title = "(untitled)"
+		}
+		fmt.Fprintf(out, "  %d. %s [%s]\n     %s\n", i+1, title, htmltext.StripControl(c.Type), htmltext.StripControl(c.URL))
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "Choose a feed [1-%d]: ", len(candidates))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", fmt.Errorf("failed to read choice: %w", err)
+			}
+			return "", fmt.Errorf("no feed chosen")
+		}
+		choice, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+		if err == nil && choice >= 1 && choice <= len(candidates) {
+			return candidates[choice-1].URL, nil
+		}
+		fmt.Fprintf(out, "Please enter a number between 1 and %d\n", len(candidates))
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// download saves the file at url to path. The data goes to path+".part"
+// first, so an interrupted download is picked up where it stopped the next
+// time, using a Range request. It returns false when path already exists.
+func (f *fetcher) download(ctx context.Context, url, path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+
+	part_path := path + ".part"
+	offset := int64(0)
+	if info, err := os.Stat(part_path); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "gator")
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	//media files are big and slow, so no overall timeout, size limit or
+	//compression here, only the transport's connect and header timeouts
+	client := &http.Client{Transport: f.client.Transport}
+	res, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer res.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case res.StatusCode == http.StatusPartialContent && offset > 0:
+		if rangeStart(res.Header.Get("Content-Range")) != offset {
+			//appending any other range would corrupt the file, start over
+			res.Body.Close()
+			err = os.Remove(part_path)
+			if err != nil {
+				return false, fmt.Errorf("failed to remove %s: %w", part_path, err)
+			}
+			return f.download(ctx, url, path)
+		}
+		flags |= os.O_APPEND
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		//the part file already holds the whole file
+		return true, os.Rename(part_path, path)
+	case res.StatusCode == http.StatusOK:
+		//the server ignored the range, start over
+		flags |= os.O_TRUNC
+	default:
+		return false, fmt.Errorf("failed to fetch %s: %s", url, res.Status)
+	}
+
+	file, err := os.OpenFile(part_path, flags, 0644)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", part_path, err)
+	}
+	_, err = io.Copy(file, res.Body)
+	close_err := file.Close()
+	if err = errors.Join(err, close_err); err != nil {
+		return false, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	err = os.Rename(part_path, path)
+	if err != nil {
+		return false, fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// rangeStart returns the first byte of a "bytes start-end/size" Content-Range
+// header, or -1 when it can't be read
+func rangeStart(contentRange string) int64 {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(contentRange), "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
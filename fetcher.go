@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/ercorn/gator/internal/config"
+)
+
+const (
+	defaultConnectTimeout   = 10 * time.Second
+	defaultReadTimeout      = 30 * time.Second
+	defaultMaxResponseBytes = 10 << 20
+	defaultMaxRetries       = 2
+	defaultRetryBaseDelay   = time.Second
+	// maxRetryAfter caps how long a Retry-After header can make us wait,
+	// a server asking for more than that is treated as a failed fetch
+	maxRetryAfter = 2 * time.Minute
+)
+
+var errResponseTooLarge = errors.New("response too large")
+
+// fetcher is the HTTP client used for feeds, with the timeouts, size limit
+// and retry policy from the "fetch" section of the config file
+type fetcher struct {
+	client           *http.Client
+	maxResponseBytes int64
+	maxRetries       int
+	retryBaseDelay   time.Duration
+}
+
+func newFetcher(cfg *config.FetchConfig) (*fetcher, error) {
+	if cfg == nil {
+		cfg = &config.FetchConfig{}
+	}
+
+	connect_timeout, err := configDuration(cfg.ConnectTimeout, defaultConnectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fetch.connect_timeout: %w", err)
+	}
+	read_timeout, err := configDuration(cfg.ReadTimeout, defaultReadTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fetch.read_timeout: %w", err)
+	}
+	retry_base_delay, err := configDuration(cfg.RetryBaseDelay, defaultRetryBaseDelay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fetch.retry_base_delay: %w", err)
+	}
+
+	f := &fetcher{
+		maxResponseBytes: defaultMaxResponseBytes,
+		maxRetries:       defaultMaxRetries,
+		retryBaseDelay:   retry_base_delay,
+	}
+	if cfg.MaxResponseBytes > 0 {
+		f.maxResponseBytes = cfg.MaxResponseBytes
+	}
+	switch {
+	case cfg.MaxRetries < 0:
+		f.maxRetries = 0
+	case cfg.MaxRetries > 0:
+		f.maxRetries = cfg.MaxRetries
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connect_timeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connect_timeout
+	transport.ResponseHeaderTimeout = read_timeout
+	//we ask for compression ourselves to add brotli, which turns off the
+	//transport's transparent gzip handling
+	transport.DisableCompression = true
+
+	f.client = &http.Client{
+		Transport: transport,
+		//covers slow bodies too, not just a slow start of the response
+		Timeout: connect_timeout + read_timeout,
+	}
+	return f, nil
+}
+
+func configDuration(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive: %s", value)
+	}
+	return d, nil
+}
+
+// do sends the request, retrying 5xx and 429 responses with jittered
+// exponential back-off or after the server's Retry-After. The last response
+// is returned when the retries run out.
+func (f *fetcher) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	for attempt := 0; ; attempt++ {
+		res, err := f.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !retryable(res.StatusCode) || attempt >= f.maxRetries {
+			return res, nil
+		}
+
+		wait, ok := retryAfter(res.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			//full jitter: anywhere between 0 and base * 2^attempt
+			wait = time.Duration(rand.Int64N(int64(backoff(f.retryBaseDelay, attempt))))
+		}
+		if wait > maxRetryAfter {
+			return res, nil
+		}
+		io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+		res.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff is base * 2^attempt, capped at maxRetryAfter so that a large
+// max_retries can't overflow it
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 0; i < attempt && d < maxRetryAfter; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryAfter)
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an
+// HTTP date
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// readBody decodes the response body according to its Content-Encoding and
+// reads at most maxResponseBytes of the decoded content
+func (f *fetcher) readBody(res *http.Response) ([]byte, error) {
+	body, err := decodeBody(res.Body, res.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, f.maxResponseBytes+1))
+	if err != nil {
+		return data, err
+	}
+	if int64(len(data)) > f.maxResponseBytes {
+		return data[:f.maxResponseBytes], fmt.Errorf("%w: more than %d bytes", errResponseTooLarge, f.maxResponseBytes)
+	}
+	return data, nil
+}
+
+func decodeBody(body io.Reader, encoding string) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return io.NopCloser(body), nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "br":
+		return io.NopCloser(brotli.NewReader(body)), nil
+	case "deflate":
+		//deflate is meant to be zlib wrapped, but some servers send a raw
+		//deflate stream, the zlib header tells them apart
+		buffered := bufio.NewReader(body)
+		header, err := buffered.Peek(2)
+		if err == nil && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 && header[0]&0x0f == 8 {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+}
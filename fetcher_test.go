@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		base    time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{time.Second, 0, time.Second},
+		{time.Second, 1, 2 * time.Second},
+		{time.Second, 5, 32 * time.Second},
+		{time.Second, 7, maxRetryAfter},
+		{time.Second, 34, maxRetryAfter},
+		{time.Second, 1000, maxRetryAfter},
+		{time.Nanosecond, 200, maxRetryAfter},
+		{time.Hour, 0, maxRetryAfter},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.base, tt.attempt); got != tt.want {
+			t.Errorf("backoff(%s, %d) = %s, want %s", tt.base, tt.attempt, got, tt.want)
+		}
+	}
+}
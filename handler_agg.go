@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
+	"github.com/google/uuid"
+)
+
+func handlerAgg(s *state, cmd command) error {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	workers := flags.Int("workers", 1, "number of feeds fetched concurrently")
+	lease := flags.Duration("lease", 10*time.Minute, "how long a claimed feed is reserved for this instance")
+	once := flags.Bool("once", false, "fetch every due feed once and exit")
+	args, err := parseFlags(flags, cmd.args)
+	if err != nil {
+		return err
+	}
+	usage := fmt.Errorf("usage: %s [--workers N] [--lease duration] [--once] <time_between_reqs>", cmd.name)
+	if len(args) > 1 || (len(args) == 0 && !*once) || *workers < 1 || *lease <= 0 {
+		return usage
+	}
+
+	//parse time_between_reqs, in one-shot mode it is optional: without it
+	//feeds that have no interval of their own are due again on the next run
+	time_between_reqs := time.Duration(0)
+	if len(args) == 1 {
+		time_between_reqs, err = time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse time: %w", err)
+		}
+	}
+	//the ticker of the loop can't run on a zero interval, in one-shot mode
+	//zero is fine and means what leaving it out does
+	if time_between_reqs < 0 || (time_between_reqs == 0 && !*once) {
+		return usage
+	}
+
+	//the first SIGINT/SIGTERM stops new fetches and lets the running ones
+	//store their posts, a second one kills the process as usual
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	sanitized, err := sanitizeStoredPosts(ctx, s)
+	if err != nil {
+		return err
+	}
+	if sanitized > 0 {
+		fmt.Printf("Sanitized the html of %d posts stored before sanitizing existed\n", sanitized)
+	}
+
+	pool := &aggregator{
+		s:        s,
+		workers:  *workers,
+		interval: time_between_reqs,
+		lease:    *lease,
+		instance: instanceName(),
+	}
+
+	if *once {
+		fmt.Printf("Collecting due feeds once with %d workers\n", *workers)
+		failed := pool.runRound(ctx)
+		if ctx.Err() != nil {
+			return fmt.Errorf("interrupted, %d feeds failed", failed)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d feeds failed", failed)
+		}
+		return nil
+	}
+
+	fmt.Printf("Collecting feeds every %s (unless set per feed) with %d workers\n", time_between_reqs.String(), *workers)
+	//check for due feeds at least every minute so shorter per feed
+	//intervals are honored
+	ticker := time.NewTicker(min(time_between_reqs, time.Minute))
+	defer ticker.Stop()
+	for {
+		fmt.Println("============================================================")
+		pool.runRound(ctx)
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("Shutting down")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// aggregator fetches every feed whose next_fetch_at has passed, spreading
+// the feeds over a fixed number of workers so at most that many fetches are
+// in flight. Feeds are claimed in the database, so several aggregators can
+// share the work.
+type aggregator struct {
+	s       *state
+	workers int
+	// interval is used for feeds without an interval of their own
+	interval time.Duration
+	// lease is how long a claim holds, a feed whose worker died becomes
+	// claimable again once it runs out
+	lease    time.Duration
+	instance string
+}
+
+// instanceName identifies this process in feeds.leased_by
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// round tracks the feeds handled by one pass, so a feed that becomes due
+// again while the pass is running isn't fetched twice
+type round struct {
+	mu      sync.Mutex
+	claimed []uuid.UUID
+	failed  int
+}
+
+func (r *round) snapshot() []uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]uuid.UUID{}, r.claimed...)
+}
+
+func (r *round) done(feedID uuid.UUID, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.claimed = append(r.claimed, feedID)
+	if err != nil {
+		r.failed++
+	}
+}
+
+// runRound starts the workers and waits until there are no due feeds left or
+// ctx is cancelled, it returns the number of feeds that failed
+func (a *aggregator) runRound(ctx context.Context) int {
+	r := &round{}
+	wg := sync.WaitGroup{}
+	for id := 1; id <= a.workers; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			a.work(ctx, r, fmt.Sprintf("%s/%d", a.instance, id), log.New(os.Stdout, fmt.Sprintf("[worker %d] ", id), 0))
+		}(id)
+	}
+	wg.Wait()
+	return r.failed
+}
+
+func (a *aggregator) work(ctx context.Context, r *round, worker string, logger *log.Logger) {
+	//bookkeeping after a fetch must not be cut short by a shutdown
+	write_ctx := context.WithoutCancel(ctx)
+
+	for ctx.Err() == nil {
+		next_feed, err := a.s.db.ClaimNextFeed(ctx, database.ClaimNextFeedParams{
+			LeaseSeconds: a.lease.Seconds(),
+			LeasedBy:     worker,
+			Claimed:      r.snapshot(),
+		})
+		if errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Println("failed to claim next feed:", err)
+			return
+		}
+
+		//a failing feed only costs this worker the one fetch
+		started_at := time.Now()
+		outcome, scrape_err := scrapeFeed(ctx, a.s, logger, next_feed)
+		if scrape_err != nil {
+			logger.Println(scrape_err)
+		}
+
+		//a fetch cut short by a shutdown is neither recorded nor rescheduled,
+		//it keeps its old next_fetch_at so it is due right away on the next run
+		if scrape_err == nil || ctx.Err() == nil {
+			a.finishFetch(write_ctx, logger, next_feed, started_at, outcome, scrape_err)
+		}
+
+		r.done(next_feed.ID, scrape_err)
+		err = a.s.db.ReleaseFeedLease(write_ctx, database.ReleaseFeedLeaseParams{
+			ID:       next_feed.ID,
+			LeasedBy: worker,
+		})
+		if err != nil {
+			logger.Println("failed to release feed lease:", err)
+		}
+	}
+}
+
+// finishFetch records the attempt and schedules the next fetch of the feed
+func (a *aggregator) finishFetch(ctx context.Context, logger *log.Logger, f database.Feed, startedAt time.Time, outcome scrapeOutcome, fetchErr error) {
+	updated, err := recordFetch(ctx, a.s, f, startedAt, outcome, fetchErr)
+	if err != nil {
+		logger.Println("failed to record fetch:", err)
+	} else {
+		f = updated
+	}
+	if f.DisabledAt.Valid {
+		logger.Printf("Feed %s disabled after %d failed fetches in a row\n", htmltext.StripControl(f.Name), f.ConsecutiveFailures)
+		return
+	}
+
+	delay, err := scheduleNextFetch(ctx, a.s, f, outcome.Hints, a.interval)
+	if err != nil {
+		logger.Println("failed to schedule next fetch:", err)
+		return
+	}
+	logger.Printf("Next fetch of %s in %s\n", htmltext.StripControl(f.Name), delay.Round(time.Second))
+}
@@ -2,16 +2,32 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"flag"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
+	"github.com/google/uuid"
 )
 
 func handlerBrowse(s *state, cmd command, user database.User) error {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	author := flags.String("author", "", "only posts by this author (any part of the name)")
+	category := flags.String("category", "", "only posts in this category")
+	args, err := parseFlags(flags, cmd.args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("usage: %s [limit] [--author name] [--category name]", cmd.name)
+	}
+
 	limit := 2
-	if len(cmd.args) == 1 {
-		arg_limit, err := strconv.Atoi((cmd.args[0]))
+	if len(args) == 1 {
+		arg_limit, err := strconv.Atoi((args[0]))
 		if err == nil {
 			limit = arg_limit
 		} else {
@@ -19,21 +35,89 @@ func handlerBrowse(s *state, cmd command, user database.User) error {
 		}
 	}
 
-	posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-		UserID: user.ID,
-		Limit:  int32(limit),
-	})
+	params := database.GetPostsForUserParams{
+		UserID:   user.ID,
+		RowLimit: int32(limit),
+	}
+	if *author != "" {
+		//matched with ILIKE, so the name's own wildcards are escaped
+		params.Author = sql.NullString{
+			String: "%" + likeEscaper.Replace(*author) + "%",
+			Valid:  true,
+		}
+	}
+	if *category != "" {
+		params.Category = sql.NullString{
+			String: *category,
+			Valid:  true,
+		}
+	}
+
+	ctx := context.Background()
+	posts, err := s.db.GetPostsForUser(ctx, params)
 	if err != nil {
 		return fmt.Errorf("couldn't get posts for user: %w", err)
 	}
 
+	post_ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		post_ids[i] = post.ID
+	}
+	enclosures, err := s.db.GetEnclosuresForPosts(ctx, post_ids)
+	if err != nil {
+		return fmt.Errorf("couldn't get enclosures: %w", err)
+	}
+	post_enclosures := map[uuid.UUID][]database.PostEnclosure{}
+	for _, enclosure := range enclosures {
+		post_enclosures[enclosure.PostID] = append(post_enclosures[enclosure.PostID], enclosure)
+	}
+	authors, err := s.db.GetAuthorsForPosts(ctx, post_ids)
+	if err != nil {
+		return fmt.Errorf("couldn't get authors: %w", err)
+	}
+	post_authors := map[uuid.UUID][]string{}
+	for _, a := range authors {
+		post_authors[a.PostID] = append(post_authors[a.PostID], a.Name)
+	}
+	categories, err := s.db.GetCategoriesForPosts(ctx, post_ids)
+	if err != nil {
+		return fmt.Errorf("couldn't get categories: %w", err)
+	}
+	post_categories := map[uuid.UUID][]string{}
+	for _, c := range categories {
+		post_categories[c.PostID] = append(post_categories[c.PostID], c.Name)
+	}
+
+	width := terminalWidth()
 	fmt.Printf("Found %d posts for user %s:\n", len(posts), user.Name)
 	for _, post := range posts {
-		fmt.Printf("--- %s ---\n", post.Title)
-		fmt.Printf("    %v\n", post.Description.String)
-		fmt.Printf("Link: %s\n", post.Url)
+		fmt.Printf("--- %s ---\n", htmltext.StripControl(post.Title))
+		fmt.Printf("ID:   %s\n", post.ID)
+		if names := post_authors[post.ID]; len(names) > 0 {
+			fmt.Printf("By:   %s\n", htmltext.StripControl(strings.Join(names, ", ")))
+		}
+		if names := post_categories[post.ID]; len(names) > 0 {
+			fmt.Printf("Tags: %s\n", htmltext.StripControl(strings.Join(names, ", ")))
+		}
+		fmt.Println(indent(htmltext.Render(post.Description.String, post.Url, width-4), "    "))
+		fmt.Printf("Link: %s\n", htmltext.StripControl(post.Url))
+		for _, enclosure := range post_enclosures[post.ID] {
+			fmt.Printf("Enclosure: %s\n", htmltext.StripControl(formatEnclosure(enclosure)))
+		}
 		fmt.Println("=====================================")
 	}
 
 	return nil
 }
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
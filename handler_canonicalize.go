@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
+	"github.com/ercorn/gator/internal/urlnorm"
+)
+
+// handlerCanonicalize is a one-off migration for feeds and posts stored before
+// urls were canonicalized: feeds whose urls are spellings of the same address
+// are merged into the oldest one, and every feed gets its normalized url and
+// canonical key stored. Then the posts of each feed get normalized urls, the
+// ones keyed by their link are rekeyed the way the fetcher keys them now and
+// posts that turn out to be the same are merged into the oldest. Every step
+// can be rerun after an error.
+func handlerCanonicalize(s *state, cmd command) error {
+	if len(cmd.args) != 0 {
+		return fmt.Errorf("usage: %s", cmd.name)
+	}
+
+	ctx := context.Background()
+	feeds, err := s.db.GetFeeds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get list of feeds: %w", err)
+	}
+	slices.SortFunc(feeds, func(a, b database.Feed) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	//group the feeds by canonical url, oldest first
+	groups := map[string][]database.Feed{}
+	keys := []string{}
+	for _, feed := range feeds {
+		_, canonical_url, err := canonicalFeedUrl(feed.Url)
+		if err != nil {
+			fmt.Printf("Skipping feed %s: %v\n", htmltext.StripControl(feed.Name), err)
+			continue
+		}
+		if _, ok := groups[canonical_url.String]; !ok {
+			keys = append(keys, canonical_url.String)
+		}
+		groups[canonical_url.String] = append(groups[canonical_url.String], feed)
+	}
+
+	merged := 0
+	for _, key := range keys {
+		group := groups[key]
+		keeper := group[0]
+		for _, duplicate := range group[1:] {
+			err := mergeFeed(ctx, s, duplicate, keeper)
+			if err != nil {
+				return fmt.Errorf("failed to merge feed %s into %s: %w", duplicate.Name, keeper.Name, err)
+			}
+			fmt.Printf("Merged %s (%s) into %s (%s)\n", htmltext.StripControl(duplicate.Name), htmltext.StripControl(duplicate.Url), htmltext.StripControl(keeper.Name), htmltext.StripControl(keeper.Url))
+			merged++
+		}
+
+		normalized, canonical_url, _ := canonicalFeedUrl(keeper.Url)
+		err := s.db.SetFeedCanonicalUrl(ctx, database.SetFeedCanonicalUrlParams{
+			ID:           keeper.ID,
+			Url:          normalized,
+			CanonicalUrl: canonical_url,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save canonical url of feed %s: %w", keeper.Name, err)
+		}
+	}
+
+	rewritten, removed := 0, 0
+	for _, key := range keys {
+		keeper := groups[key][0]
+		feed_rewritten, feed_removed, err := canonicalizePosts(ctx, s, keeper)
+		if err != nil {
+			return fmt.Errorf("failed to canonicalize posts of feed %s: %w", keeper.Name, err)
+		}
+		rewritten += feed_rewritten
+		removed += feed_removed
+	}
+
+	fmt.Printf("Canonicalized %d feeds, merged %d duplicates\n", len(keys), merged)
+	fmt.Printf("Canonicalized %d posts, merged %d duplicate posts\n", rewritten, removed)
+	return nil
+}
+
+// canonicalizePosts normalizes the urls of the feed's posts and rewrites the
+// keys migration 007 derived from them to
+// what feed.Item.Key gives now. Posts ending up with the same key are merged
+// into the oldest one. It returns how many posts were changed and removed.
+func canonicalizePosts(ctx context.Context, s *state, f database.Feed) (int, int, error) {
+	posts, err := s.db.GetPostKeysForFeed(ctx, f.ID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get posts: %w", err)
+	}
+
+	type rewrite struct {
+		post database.GetPostKeysForFeedRow
+		url  string
+		guid string
+	}
+	//posts are sorted oldest first, so the first one with a key keeps it
+	keepers := []rewrite{}
+	seen := map[string]bool{}
+	removed := 0
+	for _, post := range posts {
+		r := rewrite{post: post, url: post.Url, guid: post.Guid}
+		if normalized, err := urlnorm.Normalize(post.Url); err == nil {
+			r.url = normalized
+			if post.Guid == post.Url || post.Guid == normalized {
+				r.guid = normalized
+			}
+		}
+
+		if seen[r.guid] {
+			//deleted before any key is rewritten, so no update collides
+			err := s.db.DeletePost(ctx, post.ID)
+			if err != nil {
+				return 0, removed, fmt.Errorf("failed to delete duplicate post: %w", err)
+			}
+			removed++
+			continue
+		}
+		seen[r.guid] = true
+		keepers = append(keepers, r)
+	}
+
+	rewritten := 0
+	for _, r := range keepers {
+		if r.url == r.post.Url && r.guid == r.post.Guid {
+			continue
+		}
+		err := s.db.SetPostUrlAndGuid(ctx, database.SetPostUrlAndGuidParams{
+			ID:   r.post.ID,
+			Url:  r.url,
+			Guid: r.guid,
+		})
+		if err != nil {
+			return rewritten, removed, fmt.Errorf("failed to rewrite post %s: %w", r.post.Url, err)
+		}
+		rewritten++
+	}
+	return rewritten, removed, nil
+}
+
+// mergeFeed moves the follows, posts and urls of a duplicate feed over to the
+// feed it duplicates, then deletes it. Posts the other feed already has are
+// dropped along with the duplicate.
+func mergeFeed(ctx context.Context, s *state, duplicate, into database.Feed) error {
+	err := s.db.MoveFeedFollows(ctx, database.MoveFeedFollowsParams{
+		ToFeedID:   into.ID,
+		FromFeedID: duplicate.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move follows: %w", err)
+	}
+
+	err = s.db.MovePosts(ctx, database.MovePostsParams{
+		ToFeedID:   into.ID,
+		FromFeedID: duplicate.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move posts: %w", err)
+	}
+
+	err = s.db.MoveFeedUrlAliases(ctx, database.MoveFeedUrlAliasesParams{
+		ToFeedID:   into.ID,
+		FromFeedID: duplicate.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move url aliases: %w", err)
+	}
+
+	//keep the duplicate's url working for anyone still using it
+	err = s.db.CreateFeedUrlAlias(ctx, database.CreateFeedUrlAliasParams{
+		Url:       duplicate.Url,
+		CreatedAt: time.Now().UTC(),
+		FeedID:    into.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save url alias: %w", err)
+	}
+
+	err = s.db.DeleteFeed(ctx, duplicate.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete feed: %w", err)
+	}
+	return nil
+}
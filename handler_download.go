@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+)
+
+// handlerDownload saves the enclosures of a post, like podcast episodes, to
+// the download directory. Interrupted downloads resume when run again.
+func handlerDownload(s *state, cmd command) error {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	dir := flags.String("dir", s.cfg.DownloadDir, "directory the files are saved to")
+	args, err := parseFlags(flags, cmd.args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s <post_id> [--dir path]", cmd.name)
+	}
+	if *dir == "" {
+		*dir = "."
+	}
+
+	//an interrupt stops the download and keeps the .part file to resume from
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	post, err := findPost(ctx, s, args[0])
+	if err != nil {
+		return err
+	}
+	enclosures, err := s.db.GetEnclosuresForPosts(ctx, []uuid.UUID{post.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get enclosures: %w", err)
+	}
+	if len(enclosures) == 0 {
+		return fmt.Errorf("post %s has no enclosures", post.Title)
+	}
+
+	err = os.MkdirAll(*dir, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	for i, enclosure := range enclosures {
+		file_path := filepath.Join(*dir, enclosureFileName(enclosure.Url, post.ID, i))
+		fmt.Printf("Downloading %s to %s\n", enclosure.Url, file_path)
+		downloaded, err := s.fetcher.download(ctx, enclosure.Url, file_path)
+		if err != nil {
+			return err
+		}
+		if !downloaded {
+			fmt.Printf("%s already exists, skipping\n", file_path)
+		}
+	}
+	return nil
+}
+
+// enclosureFileName names the file after the post id and the enclosure's
+// position, which keep it unique (many shows call every episode audio.mp3,
+// or serve them all from download?id=...), followed by the file name from the
+// enclosure url when it has one
+func enclosureFileName(rawUrl string, postID uuid.UUID, index int) string {
+	prefix := fmt.Sprintf("%s-%d", postID, index+1)
+	name := ""
+	if u, err := url.Parse(rawUrl); err == nil {
+		name = path.Base(u.Path)
+	}
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return prefix
+	}
+	//never let a name from the feed point outside the download directory
+	return prefix + "-" + strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
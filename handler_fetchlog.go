@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
+	"github.com/google/uuid"
+)
+
+// handlerFetchLog prints the most recent fetch attempts, of one feed or of
+// all feeds, newest first
+func handlerFetchLog(s *state, cmd command) error {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	limit := flags.Int("limit", 20, "number of fetches to show")
+	args, err := parseFlags(flags, cmd.args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 || *limit < 1 {
+		return fmt.Errorf("usage: %s [feed_url] [--limit N]", cmd.name)
+	}
+
+	ctx := context.Background()
+	params := database.GetFeedFetchesParams{
+		RowLimit: int32(*limit),
+	}
+	if len(args) == 1 {
+		feed, err := lookupFeed(ctx, s, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get feed: %w", err)
+		}
+		params.FeedID = uuid.NullUUID{
+			UUID:  feed.ID,
+			Valid: true,
+		}
+	}
+
+	fetches, err := s.db.GetFeedFetches(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to get fetch log: %w", err)
+	}
+
+	if len(fetches) == 0 {
+		fmt.Println("No fetches found.")
+		return nil
+	}
+
+	for _, fetch := range fetches {
+		status := "-"
+		if fetch.StatusCode.Valid {
+			status = fmt.Sprint(fetch.StatusCode.Int32)
+		}
+		duration := time.Duration(fetch.DurationMs) * time.Millisecond
+
+		fmt.Printf("%s  %-4s %8s  %s\n", fetch.StartedAt.Format(time.DateTime), status, duration, htmltext.StripControl(fetch.FeedName))
+		if fetch.Error.Valid {
+			fmt.Printf("    error: %s\n", htmltext.StripControl(fetch.Error.String))
+			continue
+		}
+		seen := fetch.ItemCount - fetch.NewItemCount - fetch.UpdatedItemCount
+		fmt.Printf("    %d items: %d new, %d updated, %d seen (%d bytes)\n", fetch.ItemCount, fetch.NewItemCount, fetch.UpdatedItemCount, seen, fetch.Bytes)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
 	"github.com/google/uuid"
 )
 
@@ -28,7 +29,7 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 
 	//parse url and use it to get corresponding feed record
 	url := cmd.args[0]
-	feed, err := s.db.GetFeedByUrl(context.Background(), url)
+	feed, err := lookupFeed(context.Background(), s, url)
 	if err != nil {
 		//couldn't get feed
 		return fmt.Errorf("failed to get feed: %w", err)
@@ -48,7 +49,7 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 
 	fmt.Println("Feed followed successfully!")
 	fmt.Println("Username:", feed_follow.UserName)
-	fmt.Println("Feed name:", feed_follow.FeedName)
+	fmt.Println("Feed name:", htmltext.StripControl(feed_follow.FeedName))
 	fmt.Println("==================================================")
 
 	return nil
@@ -80,7 +81,7 @@ func handlerFollowing(s *state, cmd command, user database.User) error {
 	fmt.Println("Current username:", user.Name)
 	fmt.Println("Feed names:")
 	for _, follow := range feed_follows {
-		fmt.Println("-", follow.FeedName)
+		fmt.Println("-", htmltext.StripControl(follow.FeedName))
 	}
 	return nil
 }
@@ -90,7 +91,7 @@ func handlerUnfollow(s *state, cmd command, user database.User) error {
 		return fmt.Errorf("usage: %s <url>", cmd.name)
 	}
 
-	feed, err := s.db.GetFeedByUrl(context.Background(), cmd.args[0])
+	feed, err := lookupFeed(context.Background(), s, cmd.args[0])
 	if err != nil {
 		return fmt.Errorf("failed to get feed from url: %w", err)
 	}
@@ -103,7 +104,7 @@ func handlerUnfollow(s *state, cmd command, user database.User) error {
 		return fmt.Errorf("failed to delete feed follow: %w", err)
 	}
 
-	fmt.Println("DELETED FOLLOWED FEED:", feed.Name, "FROM USER:", user.Name)
+	fmt.Println("DELETED FOLLOWED FEED:", htmltext.StripControl(feed.Name), "FROM USER:", user.Name)
 
 	return nil
 }
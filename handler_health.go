@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
+)
+
+// handlerFeedHealth lists the feeds whose last fetches failed, disabled
+// feeds first
+func handlerFeedHealth(s *state, cmd command) error {
+	if len(cmd.args) != 0 {
+		return fmt.Errorf("usage: %s", cmd.name)
+	}
+
+	feeds, err := s.db.GetUnhealthyFeeds(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get unhealthy feeds: %w", err)
+	}
+
+	if len(feeds) == 0 {
+		fmt.Println("All feeds are healthy.")
+		return nil
+	}
+
+	fmt.Printf("Found %d feeds with failing fetches:\n", len(feeds))
+	for _, feed := range feeds {
+		printFeedHealth(feed)
+		fmt.Println("======================================================")
+	}
+	return nil
+}
+
+func printFeedHealth(feed database.Feed) {
+	fmt.Printf("* Name:          %s\n", htmltext.StripControl(feed.Name))
+	fmt.Printf("* URL:           %s\n", htmltext.StripControl(feed.Url))
+	fmt.Printf("* Failures:      %d in a row\n", feed.ConsecutiveFailures)
+	if feed.DisabledAt.Valid {
+		fmt.Printf("* Disabled:      %v\n", feed.DisabledAt.Time)
+	}
+	if feed.LastErrorAt.Valid {
+		fmt.Printf("* Last error at: %v\n", feed.LastErrorAt.Time)
+	}
+	fmt.Printf("* Last error:    %s\n", htmltext.StripControl(feed.LastError.String))
+}
+
+// handlerEnableFeed re-enables a feed that was disabled for failing too
+// often, it is fetched again on the next agg round
+func handlerEnableFeed(s *state, cmd command) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf("usage: %s <url>", cmd.name)
+	}
+
+	ctx := context.Background()
+	feed, err := lookupFeed(ctx, s, cmd.args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	feed, err = s.db.EnableFeed(ctx, feed.ID)
+	if err != nil {
+		return fmt.Errorf("failed to enable feed: %w", err)
+	}
+
+	fmt.Println("Feed enabled:", htmltext.StripControl(feed.Name))
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
+)
+
+// handlerSetInterval sets how often agg fetches a feed: a fixed duration,
+// "adaptive" to learn it from the feed's posting frequency, or "default" to
+// go back to the interval agg was started with
+func handlerSetInterval(s *state, cmd command) error {
+	if len(cmd.args) != 2 {
+		return fmt.Errorf("usage: %s <url> <duration|adaptive|default>", cmd.name)
+	}
+
+	params := database.SetFeedFetchIntervalParams{}
+	switch cmd.args[1] {
+	case "adaptive":
+		params.Adaptive = true
+	case "default":
+	default:
+		interval, err := time.ParseDuration(cmd.args[1])
+		if err != nil {
+			return fmt.Errorf("failed to parse interval: %w", err)
+		}
+		if interval < time.Second || interval.Seconds() > math.MaxInt32 {
+			return fmt.Errorf("interval out of range: %s", interval)
+		}
+		params.FetchIntervalSeconds = sql.NullInt32{
+			Int32: int32(interval.Seconds()),
+			Valid: true,
+		}
+	}
+
+	ctx := context.Background()
+	feed, err := lookupFeed(ctx, s, cmd.args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	params.ID = feed.ID
+	feed, err = s.db.SetFeedFetchInterval(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to set fetch interval: %w", err)
+	}
+
+	fmt.Printf("Feed %s will be fetched: %s\n", htmltext.StripControl(feed.Name), describeInterval(feed))
+	return nil
+}
+
+func describeInterval(feed database.Feed) string {
+	switch {
+	case feed.Adaptive:
+		return "adaptive"
+	case feed.FetchIntervalSeconds.Valid:
+		return (time.Duration(feed.FetchIntervalSeconds.Int32) * time.Second).String()
+	default:
+		return "default"
+	}
+}
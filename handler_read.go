@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/htmltext"
+	"github.com/google/uuid"
+)
+
+// handlerRead shows one post in full: the article content when the feed
+// carries it, otherwise the summary. The post is given by its id as printed
+// by browse, any unique prefix of the id works too.
+func handlerRead(s *state, cmd command) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf("usage: %s <post_id>", cmd.name)
+	}
+
+	ctx := context.Background()
+	post, err := findPost(ctx, s, cmd.args[0])
+	if err != nil {
+		return err
+	}
+	enclosures, err := s.db.GetEnclosuresForPosts(ctx, []uuid.UUID{post.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get enclosures: %w", err)
+	}
+	authors, err := s.db.GetAuthorsForPosts(ctx, []uuid.UUID{post.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get authors: %w", err)
+	}
+	categories, err := s.db.GetCategoriesForPosts(ctx, []uuid.UUID{post.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	fmt.Printf("--- %s ---\n", htmltext.StripControl(post.Title))
+	fmt.Printf("Feed:      %s\n", htmltext.StripControl(post.FeedName))
+	if len(authors) > 0 {
+		names := []string{}
+		for _, a := range authors {
+			names = append(names, a.Name)
+		}
+		fmt.Printf("By:        %s\n", htmltext.StripControl(strings.Join(names, ", ")))
+	}
+	if len(categories) > 0 {
+		names := []string{}
+		for _, c := range categories {
+			names = append(names, c.Name)
+		}
+		fmt.Printf("Tags:      %s\n", htmltext.StripControl(strings.Join(names, ", ")))
+	}
+	if post.PublishedAt.Valid {
+		fmt.Printf("Published: %v\n", post.PublishedAt.Time)
+	}
+	fmt.Printf("Link:      %s\n", htmltext.StripControl(post.Url))
+	for _, enclosure := range enclosures {
+		fmt.Printf("Enclosure: %s\n", htmltext.StripControl(formatEnclosure(enclosure)))
+	}
+	fmt.Println()
+
+	body := post.Content.String
+	if !post.Content.Valid {
+		body = post.Description.String
+	}
+	fmt.Println(htmltext.Render(body, post.Url, terminalWidth()))
+	return nil
+}
+
+// findPost looks a post up by its id or a unique prefix of it
+func findPost(ctx context.Context, s *state, id string) (database.GetPostsByIDPrefixRow, error) {
+	//the prefix goes into a LIKE pattern, so only allow what a uuid contains
+	id_prefix := strings.ToLower(id)
+	if strings.Trim(id_prefix, "0123456789abcdef-") != "" {
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("invalid post id: %s", id)
+	}
+
+	posts, err := s.db.GetPostsByIDPrefix(ctx, id_prefix)
+	if err != nil {
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("failed to get post: %w", err)
+	}
+	switch {
+	case len(posts) == 0:
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("no post found with id %s", id)
+	case len(posts) > 1:
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("more than one post id starts with %s, give more of it", id)
+	}
+	return posts[0], nil
+}
+
+// formatEnclosure gives the url of an enclosure followed by whatever the feed
+// told about it, like "https://example.com/ep1.mp3 (audio/mpeg, 24.1 MB, 41:07)"
+func formatEnclosure(e database.PostEnclosure) string {
+	details := []string{}
+	if e.MimeType != "" {
+		details = append(details, e.MimeType)
+	}
+	if e.Length.Valid {
+		details = append(details, formatSize(e.Length.Int64))
+	}
+	if e.DurationSeconds.Valid {
+		details = append(details, formatDuration(int(e.DurationSeconds.Int32)))
+	}
+	if len(details) == 0 {
+		return e.Url
+	}
+	return fmt.Sprintf("%s (%s)", e.Url, strings.Join(details, ", "))
+}
+
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
+
+// formatDuration writes seconds as M:SS, or H:MM:SS from an hour on
+func formatDuration(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+// defaultDisableAfterFailures is used when the config doesn't set
+// disable_feed_after_failures
+const defaultDisableAfterFailures = 10
+
+// disableAfterFailures is how many fetches in a row may fail before the feed
+// is disabled, a negative config value turns disabling off
+func disableAfterFailures(s *state) int32 {
+	switch {
+	case s.cfg.DisableFeedAfterFailures < 0:
+		return math.MaxInt32
+	case s.cfg.DisableFeedAfterFailures == 0:
+		return defaultDisableAfterFailures
+	default:
+		return int32(min(s.cfg.DisableFeedAfterFailures, math.MaxInt32))
+	}
+}
+
+// recordFetch stores the attempt in feed_fetches and updates the feed's
+// failure count, returning the updated feed
+func recordFetch(ctx context.Context, s *state, f database.Feed, startedAt time.Time, outcome scrapeOutcome, fetchErr error) (database.Feed, error) {
+	attempt := database.CreateFeedFetchParams{
+		ID:         uuid.New(),
+		FeedID:     f.ID,
+		StartedAt:  startedAt.UTC(),
+		DurationMs: int32(time.Since(startedAt).Milliseconds()),
+		StatusCode: sql.NullInt32{
+			Int32: int32(outcome.StatusCode),
+			Valid: outcome.StatusCode != 0,
+		},
+		Bytes:            outcome.Bytes,
+		ItemCount:        int32(outcome.Items),
+		NewItemCount:     int32(outcome.NewPosts),
+		UpdatedItemCount: int32(outcome.UpdatedPosts),
+	}
+	if fetchErr != nil {
+		attempt.Error = sql.NullString{
+			String: fetchErr.Error(),
+			Valid:  true,
+		}
+	}
+	err := s.db.CreateFeedFetch(ctx, attempt)
+	if err != nil {
+		return f, err
+	}
+
+	if fetchErr == nil {
+		return s.db.RecordFeedSuccess(ctx, f.ID)
+	}
+	return s.db.RecordFeedFailure(ctx, database.RecordFeedFailureParams{
+		LastError:    fetchErr.Error(),
+		DisableAfter: disableAfterFailures(s),
+		ID:           f.ID,
+	})
+}
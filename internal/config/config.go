@@ -10,6 +10,38 @@ import (
 type Config struct {
 	DBUrl           string `json:"db_url"`
 	CurrentUserName string `json:"current_user_name"`
+	// DateFallbackToFetchTime stores posts without a parseable date with the
+	// time they were fetched instead of a NULL published_at
+	DateFallbackToFetchTime bool `json:"date_fallback_fetch_time,omitempty"`
+	// KeepPostRevisions copies a post into post_revisions before it is
+	// overwritten by an edited version of the item
+	KeepPostRevisions bool `json:"keep_post_revisions,omitempty"`
+	// DisableFeedAfterFailures is how many fetches of a feed may fail in a
+	// row before agg stops fetching it, 0 means the default and a negative
+	// value never disables feeds
+	DisableFeedAfterFailures int `json:"disable_feed_after_failures,omitempty"`
+	// KeepRawContent stores the html of posts as the feed published it, next
+	// to the sanitized version
+	KeepRawContent bool `json:"keep_raw_content,omitempty"`
+	// TrustedIframeHosts are hosts whose iframes are kept when sanitizing
+	// posts, on top of the built-in video players
+	TrustedIframeHosts []string `json:"trusted_iframe_hosts,omitempty"`
+	// DownloadDir is where download saves enclosures, the current directory
+	// when empty
+	DownloadDir string `json:"download_dir,omitempty"`
+	// Fetch tunes the HTTP client used to download feeds
+	Fetch *FetchConfig `json:"fetch,omitempty"`
+}
+
+// FetchConfig holds the HTTP client settings, durations are strings like
+// "10s" and zero values mean the built-in defaults
+type FetchConfig struct {
+	ConnectTimeout   string `json:"connect_timeout,omitempty"`
+	ReadTimeout      string `json:"read_timeout,omitempty"`
+	MaxResponseBytes int64  `json:"max_response_bytes,omitempty"`
+	// MaxRetries applies to 5xx and 429 responses, negative turns retries off
+	MaxRetries     int    `json:"max_retries,omitempty"`
+	RetryBaseDelay string `json:"retry_base_delay,omitempty"`
 }
 
 const config_file_name = ".gatorconfig.json"
@@ -7,31 +7,104 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const claimNextFeed = `-- name: ClaimNextFeed :one
+UPDATE feeds
+SET last_fetched_at = NOW(),
+    updated_at = NOW(),
+    lease_expires_at = NOW() + make_interval(secs => $1::float8),
+    leased_by = $2::text
+WHERE id = (
+    SELECT id FROM feeds
+    WHERE feeds.disabled_at IS NULL
+        AND (feeds.lease_expires_at IS NULL OR feeds.lease_expires_at < NOW())
+        AND (feeds.next_fetch_at IS NULL OR feeds.next_fetch_at <= NOW())
+        AND NOT (feeds.id = ANY($3::uuid[]))
+    ORDER BY feeds.next_fetch_at ASC NULLS FIRST
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url
+`
+
+type ClaimNextFeedParams struct {
+	LeaseSeconds float64
+	LeasedBy     string
+	Claimed      []uuid.UUID
+}
+
+func (q *Queries) ClaimNextFeed(ctx context.Context, arg ClaimNextFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, claimNextFeed, arg.LeaseSeconds, arg.LeasedBy, pq.Array(arg.Claimed))
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, title, description, site_url, language, image_url, canonical_url)
 VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url
 `
 
 type CreateFeedParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Name         string
+	Url          string
+	UserID       uuid.UUID
+	Title        sql.NullString
+	Description  sql.NullString
+	SiteUrl      sql.NullString
+	Language     sql.NullString
+	ImageUrl     sql.NullString
+	CanonicalUrl sql.NullString
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -42,7 +115,62 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.Title,
+		arg.Description,
+		arg.SiteUrl,
+		arg.Language,
+		arg.ImageUrl,
+		arg.CanonicalUrl,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
 	)
+	return i, err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const enableFeed = `-- name: EnableFeed :one
+UPDATE feeds
+SET disabled_at = NULL, consecutive_failures = 0, next_fetch_at = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url
+`
+
+func (q *Queries) EnableFeed(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, enableFeed, id)
 	var i Feed
 	err := row.Scan(
 		&i.ID,
@@ -52,12 +180,70 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
+const getFeedByCanonicalUrl = `-- name: GetFeedByCanonicalUrl :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url FROM feeds WHERE canonical_url = $1
+`
+
+func (q *Queries) GetFeedByCanonicalUrl(ctx context.Context, canonicalUrl sql.NullString) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByCanonicalUrl, canonicalUrl)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
 
 const getFeedByUrl = `-- name: GetFeedByUrl :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds WHERE url = $1
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url FROM feeds
+WHERE url = $1
+    OR id IN (SELECT feed_id FROM feed_url_aliases WHERE feed_url_aliases.url = $1)
+LIMIT 1
 `
 
 func (q *Queries) GetFeedByUrl(ctx context.Context, url string) (Feed, error) {
@@ -71,12 +257,30 @@ func (q *Queries) GetFeedByUrl(ctx context.Context, url string) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
 
 const getFeeds = `-- name: GetFeeds :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url FROM feeds
 `
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
@@ -96,6 +300,24 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.LeaseExpiresAt,
+			&i.LeasedBy,
+			&i.FetchIntervalSeconds,
+			&i.Adaptive,
+			&i.NextFetchAt,
+			&i.ScheduleHints,
+			&i.ConsecutiveFailures,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.DisabledAt,
+			&i.Title,
+			&i.Description,
+			&i.SiteUrl,
+			&i.Language,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -110,12 +332,210 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 	return items, nil
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds ORDER BY last_fetched_at ASC NULLS FIRST LIMIT 1
+const getUnhealthyFeeds = `-- name: GetUnhealthyFeeds :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url FROM feeds
+WHERE consecutive_failures > 0 OR disabled_at IS NOT NULL
+ORDER BY disabled_at ASC NULLS LAST, consecutive_failures DESC
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
+func (q *Queries) GetUnhealthyFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getUnhealthyFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.LeaseExpiresAt,
+			&i.LeasedBy,
+			&i.FetchIntervalSeconds,
+			&i.Adaptive,
+			&i.NextFetchAt,
+			&i.ScheduleHints,
+			&i.ConsecutiveFailures,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.DisabledAt,
+			&i.Title,
+			&i.Description,
+			&i.SiteUrl,
+			&i.Language,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordFeedFailure = `-- name: RecordFeedFailure :one
+UPDATE feeds
+SET consecutive_failures = consecutive_failures + 1,
+    last_error = $1::text,
+    last_error_at = NOW(),
+    disabled_at = CASE
+        WHEN consecutive_failures + 1 >= $2::int THEN NOW()
+        ELSE disabled_at
+    END
+WHERE id = $3
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url
+`
+
+type RecordFeedFailureParams struct {
+	LastError    string
+	DisableAfter int32
+	ID           uuid.UUID
+}
+
+func (q *Queries) RecordFeedFailure(ctx context.Context, arg RecordFeedFailureParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedFailure, arg.LastError, arg.DisableAfter, arg.ID)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
+const recordFeedSuccess = `-- name: RecordFeedSuccess :one
+UPDATE feeds SET consecutive_failures = 0 WHERE id = $1 RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url
+`
+
+func (q *Queries) RecordFeedSuccess(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedSuccess, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
+const releaseFeedLease = `-- name: ReleaseFeedLease :exec
+UPDATE feeds SET lease_expires_at = NULL, leased_by = NULL WHERE id = $1 AND leased_by = $2::text
+`
+
+type ReleaseFeedLeaseParams struct {
+	ID       uuid.UUID
+	LeasedBy string
+}
+
+func (q *Queries) ReleaseFeedLease(ctx context.Context, arg ReleaseFeedLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, releaseFeedLease, arg.ID, arg.LeasedBy)
+	return err
+}
+
+const scheduleNextFetch = `-- name: ScheduleNextFetch :exec
+UPDATE feeds
+SET next_fetch_at = NOW() + make_interval(secs => $1::float8)
+WHERE id = $2
+`
+
+type ScheduleNextFetchParams struct {
+	DelaySeconds float64
+	ID           uuid.UUID
+}
+
+func (q *Queries) ScheduleNextFetch(ctx context.Context, arg ScheduleNextFetchParams) error {
+	_, err := q.db.ExecContext(ctx, scheduleNextFetch, arg.DelaySeconds, arg.ID)
+	return err
+}
+
+const setFeedCanonicalUrl = `-- name: SetFeedCanonicalUrl :exec
+UPDATE feeds SET url = $2, canonical_url = $3 WHERE id = $1
+`
+
+type SetFeedCanonicalUrlParams struct {
+	ID           uuid.UUID
+	Url          string
+	CanonicalUrl sql.NullString
+}
+
+func (q *Queries) SetFeedCanonicalUrl(ctx context.Context, arg SetFeedCanonicalUrlParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedCanonicalUrl, arg.ID, arg.Url, arg.CanonicalUrl)
+	return err
+}
+
+const setFeedFetchInterval = `-- name: SetFeedFetchInterval :one
+UPDATE feeds
+SET fetch_interval_seconds = $2, adaptive = $3, next_fetch_at = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url
+`
+
+type SetFeedFetchIntervalParams struct {
+	ID                   uuid.UUID
+	FetchIntervalSeconds sql.NullInt32
+	Adaptive             bool
+}
+
+func (q *Queries) SetFeedFetchInterval(ctx context.Context, arg SetFeedFetchIntervalParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, setFeedFetchInterval, arg.ID, arg.FetchIntervalSeconds, arg.Adaptive)
 	var i Feed
 	err := row.Scan(
 		&i.ID,
@@ -125,16 +545,96 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
 
-const markFeedFetched = `-- name: MarkFeedFetched :one
-UPDATE feeds SET last_fetched_at = NOW(), updated_at = NOW() WHERE id = $1 RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+const updateFeedCacheHeaders = `-- name: UpdateFeedCacheHeaders :exec
+UPDATE feeds SET etag = $2, last_modified = $3, updated_at = NOW() WHERE id = $1
 `
 
-func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, markFeedFetched, id)
+type UpdateFeedCacheHeadersParams struct {
+	ID           uuid.UUID
+	Etag         sql.NullString
+	LastModified sql.NullString
+}
+
+func (q *Queries) UpdateFeedCacheHeaders(ctx context.Context, arg UpdateFeedCacheHeadersParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCacheHeaders, arg.ID, arg.Etag, arg.LastModified)
+	return err
+}
+
+const updateFeedMetadata = `-- name: UpdateFeedMetadata :exec
+UPDATE feeds
+SET title = $2, description = $3, site_url = $4, language = $5, image_url = $6
+WHERE id = $1
+`
+
+type UpdateFeedMetadataParams struct {
+	ID          uuid.UUID
+	Title       sql.NullString
+	Description sql.NullString
+	SiteUrl     sql.NullString
+	Language    sql.NullString
+	ImageUrl    sql.NullString
+}
+
+func (q *Queries) UpdateFeedMetadata(ctx context.Context, arg UpdateFeedMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedMetadata,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.SiteUrl,
+		arg.Language,
+		arg.ImageUrl,
+	)
+	return err
+}
+
+const updateFeedScheduleHints = `-- name: UpdateFeedScheduleHints :exec
+UPDATE feeds SET schedule_hints = $2 WHERE id = $1
+`
+
+type UpdateFeedScheduleHintsParams struct {
+	ID            uuid.UUID
+	ScheduleHints json.RawMessage
+}
+
+func (q *Queries) UpdateFeedScheduleHints(ctx context.Context, arg UpdateFeedScheduleHintsParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedScheduleHints, arg.ID, arg.ScheduleHints)
+	return err
+}
+
+const updateFeedUrl = `-- name: UpdateFeedUrl :one
+UPDATE feeds SET url = $2, canonical_url = $3, updated_at = NOW() WHERE id = $1 RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, lease_expires_at, leased_by, fetch_interval_seconds, adaptive, next_fetch_at, schedule_hints, consecutive_failures, last_error, last_error_at, disabled_at, title, description, site_url, language, image_url, canonical_url
+`
+
+type UpdateFeedUrlParams struct {
+	ID           uuid.UUID
+	Url          string
+	CanonicalUrl sql.NullString
+}
+
+func (q *Queries) UpdateFeedUrl(ctx context.Context, arg UpdateFeedUrlParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedUrl, arg.ID, arg.Url, arg.CanonicalUrl)
 	var i Feed
 	err := row.Scan(
 		&i.ID,
@@ -144,6 +644,24 @@ func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) (Feed, erro
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.LeaseExpiresAt,
+		&i.LeasedBy,
+		&i.FetchIntervalSeconds,
+		&i.Adaptive,
+		&i.NextFetchAt,
+		&i.ScheduleHints,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.DisabledAt,
+		&i.Title,
+		&i.Description,
+		&i.SiteUrl,
+		&i.Language,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
@@ -6,19 +6,51 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Name                 string
+	Url                  string
+	UserID               uuid.UUID
+	LastFetchedAt        sql.NullTime
+	Etag                 sql.NullString
+	LastModified         sql.NullString
+	LeaseExpiresAt       sql.NullTime
+	LeasedBy             sql.NullString
+	FetchIntervalSeconds sql.NullInt32
+	Adaptive             bool
+	NextFetchAt          sql.NullTime
+	ScheduleHints        json.RawMessage
+	ConsecutiveFailures  int32
+	LastError            sql.NullString
+	LastErrorAt          sql.NullTime
+	DisabledAt           sql.NullTime
+	Title                sql.NullString
+	Description          sql.NullString
+	SiteUrl              sql.NullString
+	Language             sql.NullString
+	ImageUrl             sql.NullString
+	CanonicalUrl         sql.NullString
+}
+
+type FeedFetch struct {
+	ID               uuid.UUID
+	FeedID           uuid.UUID
+	StartedAt        time.Time
+	DurationMs       int32
+	StatusCode       sql.NullInt32
+	Bytes            int64
+	ItemCount        int32
+	Error            sql.NullString
+	NewItemCount     int32
+	UpdatedItemCount int32
 }
 
 type FeedFollow struct {
@@ -29,15 +61,63 @@ type FeedFollow struct {
 	FeedID    uuid.UUID
 }
 
+type FeedUrlAlias struct {
+	Url       string
+	CreatedAt time.Time
+	FeedID    uuid.UUID
+}
+
 type Post struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Title          string
+	Url            string
+	Description    sql.NullString
+	PublishedAt    sql.NullTime
+	FeedID         uuid.UUID
+	Guid           string
+	ContentHash    string
+	Content        sql.NullString
+	RawDescription sql.NullString
+	RawContent     sql.NullString
+	Sanitized      bool
+}
+
+type PostAuthor struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	PostID    uuid.UUID
+	Name      string
+}
+
+type PostCategory struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	PostID    uuid.UUID
+	Name      string
+}
+
+type PostEnclosure struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	PostID          uuid.UUID
+	Url             string
+	MimeType        string
+	Length          sql.NullInt64
+	DurationSeconds sql.NullInt32
+}
+
+type PostRevision struct {
 	ID          uuid.UUID
 	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	PostID      uuid.UUID
 	Title       string
 	Url         string
 	Description sql.NullString
 	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+	ContentHash string
+	Content     sql.NullString
 }
 
 type User struct {
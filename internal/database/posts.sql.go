@@ -11,10 +11,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-const createPost = `-- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+const createPost = `-- name: CreatePost :execrows
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content_hash, content, raw_description, raw_content)
 VALUES (
     $1,
     $2,
@@ -23,24 +24,34 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
 )
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+ON CONFLICT (feed_id, guid) DO NOTHING
 `
 
 type CreatePostParams struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Title          string
+	Url            string
+	Description    sql.NullString
+	PublishedAt    sql.NullTime
+	FeedID         uuid.UUID
+	Guid           string
+	ContentHash    string
+	Content        sql.NullString
+	RawDescription sql.NullString
+	RawContent     sql.NullString
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
-	row := q.db.QueryRowContext(ctx, createPost,
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPost,
 		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
@@ -49,7 +60,38 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Guid,
+		arg.ContentHash,
+		arg.Content,
+		arg.RawDescription,
+		arg.RawContent,
 	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePost = `-- name: DeletePost :exec
+DELETE FROM posts WHERE id = $1
+`
+
+func (q *Queries) DeletePost(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deletePost, id)
+	return err
+}
+
+const getPostByFeedAndGuid = `-- name: GetPostByFeedAndGuid :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content_hash, content, raw_description, raw_content, sanitized FROM posts WHERE feed_id = $1 AND guid = $2
+`
+
+type GetPostByFeedAndGuidParams struct {
+	FeedID uuid.UUID
+	Guid   string
+}
+
+func (q *Queries) GetPostByFeedAndGuid(ctx context.Context, arg GetPostByFeedAndGuidParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByFeedAndGuid, arg.FeedID, arg.Guid)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -60,40 +102,207 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Guid,
+		&i.ContentHash,
+		&i.Content,
+		&i.RawDescription,
+		&i.RawContent,
+		&i.Sanitized,
 	)
 	return i, err
 }
 
+const getPostKeysForFeed = `-- name: GetPostKeysForFeed :many
+SELECT id, created_at, url, guid FROM posts
+WHERE feed_id = $1
+ORDER BY created_at, id
+`
+
+type GetPostKeysForFeedRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Url       string
+	Guid      string
+}
+
+func (q *Queries) GetPostKeysForFeed(ctx context.Context, feedID uuid.UUID) ([]GetPostKeysForFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostKeysForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostKeysForFeedRow
+	for rows.Next() {
+		var i GetPostKeysForFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Url,
+			&i.Guid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsByIDPrefix = `-- name: GetPostsByIDPrefix :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, published_at, feed_id, guid, content_hash, content, raw_description, raw_content, sanitized, feeds.name AS feed_name FROM posts
+INNER JOIN feeds ON feeds.id = posts.feed_id
+WHERE posts.id::text LIKE $1::text || '%'
+ORDER BY posts.id
+LIMIT 2
+`
+
+type GetPostsByIDPrefixRow struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Title          string
+	Url            string
+	Description    sql.NullString
+	PublishedAt    sql.NullTime
+	FeedID         uuid.UUID
+	Guid           string
+	ContentHash    string
+	Content        sql.NullString
+	RawDescription sql.NullString
+	RawContent     sql.NullString
+	Sanitized      bool
+	FeedName       string
+}
+
+func (q *Queries) GetPostsByIDPrefix(ctx context.Context, idPrefix string) ([]GetPostsByIDPrefixRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsByIDPrefix, idPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsByIDPrefixRow
+	for rows.Next() {
+		var i GetPostsByIDPrefixRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Guid,
+			&i.ContentHash,
+			&i.Content,
+			&i.RawDescription,
+			&i.RawContent,
+			&i.Sanitized,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentPostDates = `-- name: GetRecentPostDates :many
+SELECT published_at FROM posts
+WHERE feed_id = $1 AND published_at IS NOT NULL
+ORDER BY published_at DESC LIMIT $2
+`
+
+type GetRecentPostDatesParams struct {
+	FeedID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetRecentPostDates(ctx context.Context, arg GetRecentPostDatesParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentPostDates, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var published_at sql.NullTime
+		if err := rows.Scan(&published_at); err != nil {
+			return nil, err
+		}
+		items = append(items, published_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT posts.id, posts.created_at, posts.updated_at, title, url, description, published_at, posts.feed_id, feed_follows.id, feed_follows.created_at, feed_follows.updated_at, user_id, feed_follows.feed_id FROM posts
+SELECT posts.id, posts.created_at, posts.updated_at, title, url, description, published_at, posts.feed_id, guid, content_hash, content, raw_description, raw_content, sanitized, feed_follows.id, feed_follows.created_at, feed_follows.updated_at, user_id, feed_follows.feed_id FROM posts
 INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
 WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC LIMIT $2
+    AND ($2::text IS NULL OR EXISTS (
+        SELECT 1 FROM post_authors
+        WHERE post_authors.post_id = posts.id AND post_authors.name ILIKE $2::text
+    ))
+    AND ($3::text IS NULL OR EXISTS (
+        SELECT 1 FROM post_categories
+        WHERE post_categories.post_id = posts.id AND lower(post_categories.name) = lower($3::text)
+    ))
+ORDER BY posts.published_at DESC NULLS LAST LIMIT $4
 `
 
 type GetPostsForUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID   uuid.UUID
+	Author   sql.NullString
+	Category sql.NullString
+	RowLimit int32
 }
 
 type GetPostsForUserRow struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
-	ID_2        uuid.UUID
-	CreatedAt_2 time.Time
-	UpdatedAt_2 time.Time
-	UserID      uuid.UUID
-	FeedID_2    uuid.UUID
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Title          string
+	Url            string
+	Description    sql.NullString
+	PublishedAt    sql.NullTime
+	FeedID         uuid.UUID
+	Guid           string
+	ContentHash    string
+	Content        sql.NullString
+	RawDescription sql.NullString
+	RawContent     sql.NullString
+	Sanitized      bool
+	ID_2           uuid.UUID
+	CreatedAt_2    time.Time
+	UpdatedAt_2    time.Time
+	UserID         uuid.UUID
+	FeedID_2       uuid.UUID
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.Author,
+		arg.Category,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +319,12 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Guid,
+			&i.ContentHash,
+			&i.Content,
+			&i.RawDescription,
+			&i.RawContent,
+			&i.Sanitized,
 			&i.ID_2,
 			&i.CreatedAt_2,
 			&i.UpdatedAt_2,
@@ -128,3 +343,205 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const getUnsanitizedPosts = `-- name: GetUnsanitizedPosts :many
+SELECT id, description, content FROM posts
+WHERE NOT sanitized
+ORDER BY id
+LIMIT $1
+`
+
+type GetUnsanitizedPostsRow struct {
+	ID          uuid.UUID
+	Description sql.NullString
+	Content     sql.NullString
+}
+
+func (q *Queries) GetUnsanitizedPosts(ctx context.Context, limit int32) ([]GetUnsanitizedPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnsanitizedPosts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnsanitizedPostsRow
+	for rows.Next() {
+		var i GetUnsanitizedPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Description,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUrlKeyedPosts = `-- name: GetUrlKeyedPosts :many
+SELECT url FROM posts
+WHERE feed_id = $1
+    AND guid = url
+    AND url = ANY($2::text[])
+`
+
+type GetUrlKeyedPostsParams struct {
+	FeedID uuid.UUID
+	Urls   []string
+}
+
+func (q *Queries) GetUrlKeyedPosts(ctx context.Context, arg GetUrlKeyedPostsParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getUrlKeyedPosts, arg.FeedID, pq.Array(arg.Urls))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		items = append(items, url)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const movePosts = `-- name: MovePosts :exec
+UPDATE posts SET feed_id = $1
+WHERE feed_id = $2
+    AND guid NOT IN (SELECT guid FROM posts WHERE feed_id = $1)
+`
+
+type MovePostsParams struct {
+	ToFeedID   uuid.UUID
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MovePosts(ctx context.Context, arg MovePostsParams) error {
+	_, err := q.db.ExecContext(ctx, movePosts, arg.ToFeedID, arg.FromFeedID)
+	return err
+}
+
+const rekeyLegacyPost = `-- name: RekeyLegacyPost :execrows
+UPDATE posts SET guid = $1
+WHERE id = (
+    SELECT legacy.id FROM posts AS legacy
+    WHERE legacy.feed_id = $2
+        AND legacy.guid = legacy.url
+        AND legacy.guid <> $1
+        AND legacy.url = ANY($3::text[])
+    ORDER BY legacy.created_at
+    LIMIT 1
+)
+AND NOT EXISTS (
+    SELECT 1 FROM posts AS keyed
+    WHERE keyed.feed_id = $2 AND keyed.guid = $1
+)
+`
+
+type RekeyLegacyPostParams struct {
+	Guid   string
+	FeedID uuid.UUID
+	Urls   []string
+}
+
+func (q *Queries) RekeyLegacyPost(ctx context.Context, arg RekeyLegacyPostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rekeyLegacyPost, arg.Guid, arg.FeedID, pq.Array(arg.Urls))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setPostSanitized = `-- name: SetPostSanitized :exec
+UPDATE posts SET
+    description = $1,
+    content = $2,
+    raw_description = COALESCE(raw_description, $3),
+    raw_content = COALESCE(raw_content, $4),
+    sanitized = true
+WHERE id = $5
+`
+
+type SetPostSanitizedParams struct {
+	Description    sql.NullString
+	Content        sql.NullString
+	RawDescription sql.NullString
+	RawContent     sql.NullString
+	ID             uuid.UUID
+}
+
+func (q *Queries) SetPostSanitized(ctx context.Context, arg SetPostSanitizedParams) error {
+	_, err := q.db.ExecContext(ctx, setPostSanitized,
+		arg.Description,
+		arg.Content,
+		arg.RawDescription,
+		arg.RawContent,
+		arg.ID,
+	)
+	return err
+}
+
+const setPostUrlAndGuid = `-- name: SetPostUrlAndGuid :exec
+UPDATE posts SET url = $2, guid = $3
+WHERE id = $1
+`
+
+type SetPostUrlAndGuidParams struct {
+	ID   uuid.UUID
+	Url  string
+	Guid string
+}
+
+func (q *Queries) SetPostUrlAndGuid(ctx context.Context, arg SetPostUrlAndGuidParams) error {
+	_, err := q.db.ExecContext(ctx, setPostUrlAndGuid, arg.ID, arg.Url, arg.Guid)
+	return err
+}
+
+const updatePost = `-- name: UpdatePost :exec
+UPDATE posts
+SET title = $2, url = $3, description = $4, published_at = $5, content_hash = $6, updated_at = $7, content = $8, raw_description = $9, raw_content = $10, sanitized = true
+WHERE id = $1
+`
+
+type UpdatePostParams struct {
+	ID             uuid.UUID
+	Title          string
+	Url            string
+	Description    sql.NullString
+	PublishedAt    sql.NullTime
+	ContentHash    string
+	UpdatedAt      time.Time
+	Content        sql.NullString
+	RawDescription sql.NullString
+	RawContent     sql.NullString
+}
+
+func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) error {
+	_, err := q.db.ExecContext(ctx, updatePost,
+		arg.ID,
+		arg.Title,
+		arg.Url,
+		arg.Description,
+		arg.PublishedAt,
+		arg.ContentHash,
+		arg.UpdatedAt,
+		arg.Content,
+		arg.RawDescription,
+		arg.RawContent,
+	)
+	return err
+}
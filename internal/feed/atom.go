@@ -0,0 +1,180 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// atomText holds an Atom text construct (title, summary, content). Plain text
+// and escaped html end up in Text, xhtml content is kept as raw markup in Inner.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// PlainText is the text construct without markup, for titles: those are
+// printed as they are and html ones often carry entities like &#8217;
+func (t atomText) PlainText() string {
+	switch t.Type {
+	case "html":
+		return htmlToText(t.Text)
+	case "xhtml":
+		return htmlToText(t.Inner)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// htmlToText drops the tags from an html fragment and decodes its entities
+func htmlToText(src string) string {
+	text := strings.Builder{}
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(text.String()), " ")
+		case html.TextToken:
+			text.Write(z.Text())
+		}
+	}
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      atomText       `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name     `xml:"feed"`
+	Lang     string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Title    atomText     `xml:"title"`
+	Subtitle atomText     `xml:"subtitle"`
+	Links    []atomLink   `xml:"link"`
+	Authors  []atomPerson `xml:"author"`
+	Logo     string       `xml:"logo"`
+	Icon     string       `xml:"icon"`
+	Entry    []atomEntry  `xml:"entry"`
+}
+
+// atomAlternateLink picks the link pointing at the html version of the
+// resource, a link without rel counts as alternate per RFC 4287
+func atomAlternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+func atomEnclosures(links []atomLink) []Enclosure {
+	enclosures := []Enclosure{}
+	for _, link := range links {
+		if link.Rel == "enclosure" && strings.TrimSpace(link.Href) != "" {
+			enclosures = append(enclosures, Enclosure{
+				URL:    strings.TrimSpace(link.Href),
+				Type:   strings.TrimSpace(link.Type),
+				Length: parseLength(link.Length),
+			})
+		}
+	}
+	return enclosures
+}
+
+// AtomParser handles Atom 1.0 (RFC 4287)
+type AtomParser struct{}
+
+func init() {
+	Register(AtomParser{})
+}
+
+func (AtomParser) Name() string {
+	return "atom"
+}
+
+// Detect also takes a <feed> missing its namespace declaration, but only when
+// the server says it is Atom, other formats use that element name too
+func (AtomParser) Detect(s Sniff) bool {
+	if s.Root.Local != "feed" {
+		return false
+	}
+	return s.Root.Space == atomNamespace || (s.Root.Space == "" && s.MediaType == "application/atom+xml")
+}
+
+func (AtomParser) Parse(body []byte) (*Feed, error) {
+	doc := atomFeed{}
+	err := newXMLDecoder(body).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Feed{
+		Title:       doc.Title.PlainText(),
+		Link:        atomAlternateLink(doc.Links),
+		Description: doc.Subtitle.String(),
+		Language:    strings.TrimSpace(doc.Lang),
+		Image:       strings.TrimSpace(doc.Logo),
+	}
+	if f.Image == "" {
+		f.Image = strings.TrimSpace(doc.Icon)
+	}
+	for _, entry := range doc.Entry {
+		description := entry.Summary.String()
+		if description == "" {
+			description = entry.Content.String()
+		}
+
+		date := entry.Published
+		if date == "" {
+			date = entry.Updated
+		}
+		published, _ := ParseDate(date)
+
+		//entries without an author inherit the feed's (RFC 4287 4.2.1)
+		authors := entry.Authors
+		if len(authors) == 0 {
+			authors = doc.Authors
+		}
+
+		f.Items = append(f.Items, Item{
+			GUID:        strings.TrimSpace(entry.ID),
+			Title:       entry.Title.PlainText(),
+			Link:        atomAlternateLink(entry.Links),
+			Description: description,
+			Content:     entry.Content.String(),
+			Published:   published,
+			Enclosures:  atomEnclosures(entry.Links),
+			Authors:     uniqueNames(atomPeopleNames(authors)),
+			Categories:  uniqueNames(atomCategoryNames(entry.Categories)),
+		})
+	}
+	return f, nil
+}
@@ -0,0 +1,105 @@
+package feed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAtomParse(t *testing.T) {
+	f, err := AtomParser{}.Parse(readFixture(t, "atom-entries.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Title != "Tom & Jerry" {
+		t.Errorf("Title = %q, want %q", f.Title, "Tom & Jerry")
+	}
+	if f.Link != "https://example.com/" {
+		t.Errorf("Link = %q, want the alternate link", f.Link)
+	}
+	if f.Language != "en" {
+		t.Errorf("Language = %q, want en", f.Language)
+	}
+
+	tests := []struct {
+		guid        string
+		title       string
+		link        string
+		description string
+		content     string
+		published   time.Time
+	}{
+		{
+			guid:        "tag:example.com,2024:1",
+			title:       "Alternate after self",
+			link:        "https://example.com/posts/1",
+			description: "The summary.",
+			content:     "<p>The full text.</p>",
+			published:   time.Date(2024, time.March, 5, 14, 30, 15, 0, time.UTC),
+		},
+		{
+			//no rel means alternate, updated stands in for published and
+			//content for the missing summary
+			guid:        "tag:example.com,2024:2",
+			title:       "Link without rel",
+			link:        "https://example.com/posts/2",
+			description: "<p>Only content.</p>",
+			content:     "<p>Only content.</p>",
+			published:   time.Date(2024, time.March, 6, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			//without an alternate link the first one is used, an
+			//unparseable date leaves Published zero
+			guid:        "tag:example.com,2024:3",
+			title:       "Only a related link",
+			link:        "https://example.org/elsewhere",
+			description: `<div xmlns="http://www.w3.org/1999/xhtml"><p>Inline <b>xhtml</b></p></div>`,
+			content:     `<div xmlns="http://www.w3.org/1999/xhtml"><p>Inline <b>xhtml</b></p></div>`,
+		},
+		{
+			//html titles are turned into plain text, escaped markup in
+			//other html text constructs stays escaped
+			guid:        "tag:example.com,2024:4",
+			title:       "Escaped <b> markup",
+			link:        "https://example.com/posts/4",
+			description: "Shows &amp;lt;b&amp;gt; literally",
+		},
+		{
+			guid:  "tag:example.com,2024:5",
+			title: "Don\u2019t panic",
+			link:  "https://example.com/posts/5",
+		},
+		{
+			guid:  "tag:example.com,2024:6",
+			title: "Inline xhtml & more",
+			link:  "https://example.com/posts/6",
+		},
+	}
+
+	if len(f.Items) != len(tests) {
+		t.Fatalf("got %d items, want %d", len(f.Items), len(tests))
+	}
+	for i, tt := range tests {
+		item := f.Items[i]
+		t.Run(tt.title, func(t *testing.T) {
+			if item.GUID != tt.guid {
+				t.Errorf("GUID = %q, want %q", item.GUID, tt.guid)
+			}
+			if item.Title != tt.title {
+				t.Errorf("Title = %q, want %q", item.Title, tt.title)
+			}
+			if item.Link != tt.link {
+				t.Errorf("Link = %q, want %q", item.Link, tt.link)
+			}
+			if item.Description != tt.description {
+				t.Errorf("Description = %q, want %q", item.Description, tt.description)
+			}
+			if item.Content != tt.content {
+				t.Errorf("Content = %q, want %q", item.Content, tt.content)
+			}
+			if !item.Published.Equal(tt.published) {
+				t.Errorf("Published = %s, want %s", item.Published, tt.published)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package feed
+
+import (
+	"regexp"
+	"strings"
+)
+
+// atomPerson is an Atom author or contributor, JSON Feed authors have the
+// same shape
+type atomPerson struct {
+	Name  string `xml:"name" json:"name"`
+	Email string `xml:"email" json:"-"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// rssEmailAuthor matches the "jane@example.com (Jane Doe)" form RSS 2.0 asks
+// for in <author>
+var rssEmailAuthor = regexp.MustCompile(`^\S+@\S+\s*\((.+)\)$`)
+
+// rssAuthorName returns the name part of an RSS <author>, or the address when
+// that is all there is
+func rssAuthorName(author string) string {
+	author = strings.TrimSpace(author)
+	if match := rssEmailAuthor.FindStringSubmatch(author); match != nil {
+		return match[1]
+	}
+	return author
+}
+
+func atomPeopleNames(people []atomPerson) []string {
+	names := []string{}
+	for _, person := range people {
+		name := person.Name
+		if strings.TrimSpace(name) == "" {
+			name = person.Email
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// atomCategoryNames uses the machine-readable term, the label is only there
+// for display and is often missing
+func atomCategoryNames(categories []atomCategory) []string {
+	names := []string{}
+	for _, category := range categories {
+		name := category.Term
+		if strings.TrimSpace(name) == "" {
+			name = category.Label
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// uniqueNames cleans up author and category names: whitespace is collapsed,
+// empty names dropped and names repeated in a different case kept once
+func uniqueNames(lists ...[]string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, name := range list {
+			name = strings.Join(strings.Fields(name), " ")
+			key := strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,73 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+var xmlEncodingDecl = regexp.MustCompile(`^\s*<\?xml[^>]*\sencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// documentCharset works out the encoding of the body: a byte order mark wins,
+// then the charset parameter of the Content-Type header, then the encoding in
+// the XML declaration. An empty result means UTF-8.
+func documentCharset(contentType string, body []byte) string {
+	switch {
+	case bytes.HasPrefix(body, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8"
+	case bytes.HasPrefix(body, []byte{0xFE, 0xFF}):
+		return "utf-16be"
+	case bytes.HasPrefix(body, []byte{0xFF, 0xFE}):
+		return "utf-16le"
+	}
+
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return strings.ToLower(params["charset"])
+	}
+
+	//the declaration itself is ascii in every encoding we care about
+	head := body[:min(len(body), 512)]
+	if m := xmlEncodingDecl.FindSubmatch(head); m != nil {
+		return strings.ToLower(string(m[1]))
+	}
+	return ""
+}
+
+// toUTF8 transcodes the body to UTF-8 so that the parsers only ever see one
+// encoding, whatever the feed was published in
+func toUTF8(contentType string, body []byte) ([]byte, error) {
+	label := documentCharset(contentType, body)
+	if label == "" || label == "utf-8" || label == "utf8" {
+		return bytes.TrimPrefix(body, []byte{0xEF, 0xBB, 0xBF}), nil
+	}
+
+	encoding, name := charset.Lookup(label)
+	if encoding == nil {
+		return nil, fmt.Errorf("unsupported charset %q", label)
+	}
+	if name == "utf-8" {
+		return body, nil
+	}
+
+	decoded, err := io.ReadAll(encoding.NewDecoder().Reader(bytes.NewReader(body)))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", name, err)
+	}
+	return bytes.TrimPrefix(decoded, []byte{0xEF, 0xBB, 0xBF}), nil
+}
+
+// newXMLDecoder returns a decoder for a body that toUTF8 already transcoded,
+// the encoding named in its XML declaration no longer applies
+func newXMLDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder
+}
@@ -0,0 +1,151 @@
+package feed
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dateLayouts is tried in order after normalizeDate has dropped the weekday
+// and turned named zones into numeric offsets. It covers RFC 822/1123 as used
+// by pubDate (with 2 or 4 digit years and with or without seconds), RFC 850,
+// ISO 8601 as used by Atom, JSON Feed and dc:date, and the malformed variants
+// that show up in real feeds.
+var dateLayouts = []string{
+	//RFC 822 / 1123 family
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -07:00",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 January 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 January 2006 15:04:05",
+	"2 Jan 2006",
+	"2 January 2006",
+
+	//RFC 850
+	"2-Jan-06 15:04:05 -0700",
+	"2-Jan-2006 15:04:05 -0700",
+
+	//ISO 8601 / W3CDTF
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+
+	//ANSIC, UnixDate and spelled out month first dates
+	"Jan 2 15:04:05 2006",
+	"Jan 2 15:04:05 -0700 2006",
+	"Jan 2, 2006 15:04:05 -0700",
+	"Jan 2, 2006 15:04:05",
+	"Jan 2, 2006 15:04",
+	"Jan 2, 2006",
+	"January 2, 2006 15:04:05 -0700",
+	"January 2, 2006 15:04:05",
+	"January 2, 2006",
+}
+
+// zoneOffsets maps the zone names found in feeds to numeric offsets, Go only
+// knows the offset of a named zone when it matches the local one
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"AKST": "-0900",
+	"AKDT": "-0800",
+	"HST":  "-1000",
+	"BST":  "+0100",
+	"WET":  "+0000",
+	"WEST": "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"MET":  "+0100",
+	"MEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"MSK":  "+0300",
+	"JST":  "+0900",
+	"KST":  "+0900",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+	"NZST": "+1200",
+	"NZDT": "+1300",
+}
+
+var (
+	weekdayPrefix  = regexp.MustCompile(`(?i)^(mon|tue|wed|thu|fri|sat|sun)[a-z]*\.?,?\s+`)
+	zoneComment    = regexp.MustCompile(`\s*\([^)]*\)$`)
+	gmtOffsetZone  = regexp.MustCompile(`(?i)^(GMT|UTC)([+-]\d{1,2})(:?(\d\d))?$`)
+	repeatedSpaces = regexp.MustCompile(`\s+`)
+	isoDate        = regexp.MustCompile(`^\d{4}-\d\d`)
+)
+
+// normalizeDate drops the (often wrong) weekday and trailing comments and
+// replaces named zones so that the layouts only have to deal with offsets
+func normalizeDate(value string) string {
+	value = strings.TrimSpace(repeatedSpaces.ReplaceAllString(value, " "))
+	value = weekdayPrefix.ReplaceAllString(value, "")
+	value = zoneComment.ReplaceAllString(value, "")
+	if isoDate.MatchString(value) {
+		//RFC 3339 allows a lowercase t and z, Go only parses them uppercase
+		value = strings.ToUpper(value)
+	}
+
+	fields := strings.Split(value, " ")
+	for i, field := range fields {
+		if offset, ok := zoneOffsets[strings.ToUpper(field)]; ok && i > 0 {
+			fields[i] = offset
+			continue
+		}
+		if m := gmtOffsetZone.FindStringSubmatch(field); m != nil && i > 0 {
+			hours := m[2]
+			if len(hours) == 2 {
+				hours = hours[:1] + "0" + hours[1:]
+			}
+			minutes := m[4]
+			if minutes == "" {
+				minutes = "00"
+			}
+			fields[i] = hours + minutes
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// ParseDate parses a feed date in any of the known layouts. Dates without a
+// zone are taken to be UTC. It reports false when no layout matched.
+func ParseDate(value string) (time.Time, bool) {
+	value = normalizeDate(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		//one row per entry of dateLayouts, in the same order
+		{"rfc1123", "Tue, 05 Mar 2024 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"rfc1123 colon offset", "Tue, 05 Mar 2024 14:30:15 +01:00", "2024-03-05T14:30:15+01:00"},
+		{"rfc1123 no seconds", "Tue, 05 Mar 2024 14:30 +0100", "2024-03-05T14:30:00+01:00"},
+		{"rfc822 two digit year", "05 Mar 24 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"rfc822 two digit year no seconds", "05 Mar 24 14:30 +0100", "2024-03-05T14:30:00+01:00"},
+		{"long month", "5 March 2024 14:30:15 -0500", "2024-03-05T14:30:15-05:00"},
+		{"long month no seconds", "5 March 2024 14:30 -0500", "2024-03-05T14:30:00-05:00"},
+		{"no zone", "05 Mar 2024 14:30:15", "2024-03-05T14:30:15Z"},
+		{"no zone no seconds", "05 Mar 2024 14:30", "2024-03-05T14:30:00Z"},
+		{"long month no zone", "5 March 2024 14:30:15", "2024-03-05T14:30:15Z"},
+		{"date only", "05 Mar 2024", "2024-03-05T00:00:00Z"},
+		{"long month date only", "5 March 2024", "2024-03-05T00:00:00Z"},
+		{"rfc850", "Tuesday, 05-Mar-24 14:30:15 GMT", "2024-03-05T14:30:15Z"},
+		{"rfc850 four digit year", "05-Mar-2024 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"rfc3339 nano", "2024-03-05T14:30:15.123456789+01:00", "2024-03-05T14:30:15.123456789+01:00"},
+		{"rfc3339", "2024-03-05T14:30:15Z", "2024-03-05T14:30:15Z"},
+		{"iso offset without colon", "2024-03-05T14:30:15+0100", "2024-03-05T14:30:15+01:00"},
+		{"iso fraction offset without colon", "2024-03-05T14:30:15.5+0100", "2024-03-05T14:30:15.5+01:00"},
+		{"iso no seconds", "2024-03-05T14:30+01:00", "2024-03-05T14:30:00+01:00"},
+		{"iso fraction no zone", "2024-03-05T14:30:15.250", "2024-03-05T14:30:15.25Z"},
+		{"iso no zone", "2024-03-05T14:30:15", "2024-03-05T14:30:15Z"},
+		{"iso no zone no seconds", "2024-03-05T14:30", "2024-03-05T14:30:00Z"},
+		{"space separated offset", "2024-03-05 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"space separated rfc3339 zone", "2024-03-05 14:30:15+01:00", "2024-03-05T14:30:15+01:00"},
+		{"space separated no zone", "2024-03-05 14:30:15", "2024-03-05T14:30:15Z"},
+		{"space separated no seconds", "2024-03-05 14:30", "2024-03-05T14:30:00Z"},
+		{"iso date", "2024-03-05", "2024-03-05T00:00:00Z"},
+		{"iso month", "2024-03", "2024-03-01T00:00:00Z"},
+		{"iso year", "2024", "2024-01-01T00:00:00Z"},
+		{"ansic", "Tue Mar 5 14:30:15 2024", "2024-03-05T14:30:15Z"},
+		{"unix date", "Tue Mar 5 14:30:15 EST 2024", "2024-03-05T14:30:15-05:00"},
+		{"month first offset", "Mar 5, 2024 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"month first", "Mar 5, 2024 14:30:15", "2024-03-05T14:30:15Z"},
+		{"month first no seconds", "Mar 5, 2024 14:30", "2024-03-05T14:30:00Z"},
+		{"month first date only", "Mar 5, 2024", "2024-03-05T00:00:00Z"},
+		{"long month first offset", "March 5, 2024 14:30:15 -0800", "2024-03-05T14:30:15-08:00"},
+		{"long month first", "March 5, 2024 14:30:15", "2024-03-05T14:30:15Z"},
+		{"long month first date only", "March 5, 2024", "2024-03-05T00:00:00Z"},
+
+		//named zones
+		{"named zone pst", "Tue, 05 Mar 2024 14:30:15 PST", "2024-03-05T14:30:15-08:00"},
+		{"named zone cest", "Tue, 05 Mar 2024 14:30:15 CEST", "2024-03-05T14:30:15+02:00"},
+		{"named zone ut", "Tue, 05 Mar 2024 14:30:15 UT", "2024-03-05T14:30:15Z"},
+		{"named zone lowercase", "Tue, 05 Mar 2024 14:30:15 gmt", "2024-03-05T14:30:15Z"},
+
+		//zone names with an offset
+		{"gmt plus hours", "Tue, 05 Mar 2024 14:30:15 GMT+2", "2024-03-05T14:30:15+02:00"},
+		{"gmt minus hours and minutes", "Tue, 05 Mar 2024 14:30:15 GMT-05:30", "2024-03-05T14:30:15-05:30"},
+		{"utc plus two digit hours", "Tue, 05 Mar 2024 14:30:15 UTC+10", "2024-03-05T14:30:15+10:00"},
+
+		//weekdays
+		{"long weekday", "Tuesday, 05 Mar 2024 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"weekday with dot", "Tue. 05 Mar 2024 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"weekday without comma", "tue 05 Mar 2024 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+		{"wrong weekday", "Fri, 05 Mar 2024 14:30:15 +0100", "2024-03-05T14:30:15+01:00"},
+
+		//trailing zone comments
+		{"utc comment", "Tue, 05 Mar 2024 14:30:15 +0000 (UTC)", "2024-03-05T14:30:15Z"},
+		{"long zone comment", "2024-03-05 14:30:15 +0100 (Central European Time)", "2024-03-05T14:30:15+01:00"},
+
+		//sloppy input
+		{"extra spaces", "  Tue,  05 Mar 2024   14:30:15 +0100 ", "2024-03-05T14:30:15+01:00"},
+		{"lowercase t and z", "2024-03-05t14:30:15z", "2024-03-05T14:30:15Z"},
+		{"lowercase z with fraction", "2024-03-05T14:30:15.5z", "2024-03-05T14:30:15.5Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseDate(tt.value)
+			if !ok {
+				t.Fatalf("ParseDate(%q) failed, want %s", tt.value, tt.want)
+			}
+			if got.Format(time.RFC3339Nano) != tt.want {
+				t.Errorf("ParseDate(%q) = %s, want %s", tt.value, got.Format(time.RFC3339Nano), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"not a date",
+		"yesterday",
+		"32 Mar 2024",
+		"2024-13-01",
+		"2024-03-05T25:00:00Z",
+		"Tue, 05 Mar 2024 14:30:15 XYZ",
+		"Tue, 05 Foo 2024 14:30:15 +0100",
+		"(UTC)",
+	}
+
+	for _, value := range tests {
+		if got, ok := ParseDate(value); ok {
+			t.Errorf("ParseDate(%q) = %s, want failure", value, got)
+		}
+	}
+}
+
+// TestParseDateLayouts makes sure every layout is reachable: a date written
+// in the layout has to parse back to the same time
+func TestParseDateLayouts(t *testing.T) {
+	want := time.Date(2024, time.March, 5, 14, 30, 15, 0, time.FixedZone("", 3600))
+	for _, layout := range dateLayouts {
+		value := want.Format(layout)
+		got, ok := ParseDate(value)
+		if !ok {
+			t.Errorf("layout %q: ParseDate(%q) failed", layout, value)
+			continue
+		}
+		if reparsed, _ := time.Parse(layout, value); !got.Equal(reparsed) {
+			t.Errorf("layout %q: ParseDate(%q) = %s, want %s", layout, value, got, reparsed)
+		}
+	}
+}
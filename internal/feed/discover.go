@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// Link is a feed advertised by a web page
+type Link struct {
+	URL   string
+	Type  string
+	Title string
+}
+
+// feedLinkTypes are the <link rel="alternate"> types that point at a feed
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/rdf+xml":   true,
+	"application/feed+json": true,
+}
+
+// CommonFeedPaths are where sites that don't advertise their feed usually
+// publish it, relative to the site root
+var CommonFeedPaths = []string{
+	"/feed",
+	"/rss",
+	"/feed.xml",
+	"/rss.xml",
+	"/atom.xml",
+	"/index.xml",
+	"/feed.json",
+}
+
+// IsHTML reports whether the response is a web page rather than a feed
+func IsHTML(contentType string, body []byte) bool {
+	media_type, _, err := mime.ParseMediaType(contentType)
+	if err != nil || media_type == "" {
+		media_type, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	}
+	switch strings.ToLower(media_type) {
+	case "text/html", "application/xhtml+xml":
+		return true
+	}
+	//xhtml is sometimes served as plain xml
+	return strings.EqualFold(SniffDocument(contentType, body).Root.Local, "html")
+}
+
+// DiscoverLinks returns the feeds a web page links to with
+// <link rel="alternate">, resolved against the page URL (or its <base>)
+func DiscoverLinks(pageURL *url.URL, contentType string, body []byte) ([]Link, error) {
+	reader, err := charset.NewReader(bytes.NewReader(body), contentType)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := html.Parse(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	base := pageURL
+	links := []Link{}
+	seen := map[string]bool{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Base:
+				if href := attr(n, "href"); href != "" {
+					if resolved, err := pageURL.Parse(href); err == nil {
+						base = resolved
+					}
+				}
+			case atom.Link:
+				if link, ok := feedLink(n, base); ok && !seen[link.URL] {
+					seen[link.URL] = true
+					links = append(links, link)
+				}
+			case atom.Body:
+				//feeds are only advertised in the head
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return links, nil
+}
+
+func feedLink(n *html.Node, base *url.URL) (Link, bool) {
+	alternate := false
+	for _, rel := range strings.Fields(attr(n, "rel")) {
+		if strings.EqualFold(rel, "alternate") {
+			alternate = true
+		}
+	}
+	link_type := strings.ToLower(strings.TrimSpace(attr(n, "type")))
+	href := strings.TrimSpace(attr(n, "href"))
+	if !alternate || !feedLinkTypes[link_type] || href == "" {
+		return Link{}, false
+	}
+
+	resolved, err := base.Parse(href)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return Link{}, false
+	}
+	return Link{
+		URL:   resolved.String(),
+		Type:  link_type,
+		Title: strings.TrimSpace(attr(n, "title")),
+	}, true
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
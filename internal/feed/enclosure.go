@@ -0,0 +1,104 @@
+package feed
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Enclosure is a media file attached to an item, like a podcast episode.
+// Length (in bytes) and Duration are 0 when the feed doesn't give them.
+type Enclosure struct {
+	URL      string
+	Type     string
+	Length   int64
+	Duration time.Duration
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// mediaContent is a Media RSS media:content element, either directly in the
+// item or in a media:group
+type mediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Duration string `xml:"duration,attr"`
+}
+
+// mediaElements are the enclosure-like elements RSS items carry
+type mediaElements struct {
+	Enclosures     []rssEnclosure `xml:"enclosure"`
+	MediaContent   []mediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroup     []mediaContent `xml:"http://search.yahoo.com/mrss/ group>content"`
+	ITunesDuration string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+}
+
+// enclosures merges <enclosure> and media:content, the same file is often
+// listed in both. itunes:duration applies to the item's main enclosure.
+func (m mediaElements) enclosures() []Enclosure {
+	enclosures := []Enclosure{}
+	seen := map[string]bool{}
+	add := func(e Enclosure) {
+		e.URL = strings.TrimSpace(e.URL)
+		if e.URL == "" || seen[e.URL] {
+			return
+		}
+		seen[e.URL] = true
+		enclosures = append(enclosures, e)
+	}
+
+	for _, e := range m.Enclosures {
+		add(Enclosure{
+			URL:    e.URL,
+			Type:   strings.TrimSpace(e.Type),
+			Length: parseLength(e.Length),
+		})
+	}
+	for _, contents := range [][]mediaContent{m.MediaContent, m.MediaGroup} {
+		for _, c := range contents {
+			add(Enclosure{
+				URL:      c.URL,
+				Type:     strings.TrimSpace(c.Type),
+				Length:   parseLength(c.FileSize),
+				Duration: parseDuration(c.Duration),
+			})
+		}
+	}
+
+	if len(enclosures) > 0 && enclosures[0].Duration == 0 {
+		enclosures[0].Duration = parseDuration(m.ITunesDuration)
+	}
+	return enclosures
+}
+
+func parseLength(value string) int64 {
+	length, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || length < 0 {
+		return 0
+	}
+	return length
+}
+
+// parseDuration reads itunes:duration style durations: plain seconds or
+// [[HH:]MM:]SS, optionally with fractional seconds
+func parseDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	total := 0.0
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total * float64(time.Second)).Round(time.Second)
+}
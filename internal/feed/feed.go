@@ -0,0 +1,50 @@
+// Package feed parses syndication documents (RSS 2.0, Atom, RSS 1.0/RDF and
+// JSON Feed) into one normalized model that the aggregator stores as posts.
+package feed
+
+import (
+	"html"
+	"time"
+)
+
+// Feed is the format independent view of a fetched feed document. Link is
+// the site the feed belongs to and Image its logo or icon, if it has one.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Language    string
+	Image       string
+	Items       []Item
+	Schedule    ScheduleHints
+}
+
+// Item is a single entry of a feed. Description is the summary shown when
+// browsing, Content the full article when the feed carries it (left as the
+// publisher's html). Published is the zero time when the document had no
+// usable date for it. Authors and Categories hold each name once.
+type Item struct {
+	GUID        string
+	Title       string
+	Link        string
+	Description string
+	Content     string
+	Published   time.Time
+	Enclosures  []Enclosure
+	Authors     []string
+	Categories  []string
+}
+
+// unescapeHTML decodes escaped HTML entities (like &ldquo;) left in the
+// Title and Description fields of the feed and the individual items. Only RSS
+// (2.0 and 1.0) gets this second pass: publishers double escape its untyped
+// text, while Atom text constructs say what they hold and are used as
+// encoding/xml decoded them, otherwise escaped markup would turn into real tags.
+func unescapeHTML(f *Feed) {
+	f.Title = html.UnescapeString(f.Title)
+	f.Description = html.UnescapeString(f.Description)
+	for i := range f.Items {
+		f.Items[i].Title = html.UnescapeString(f.Items[i].Title)
+		f.Items[i].Description = html.UnescapeString(f.Items[i].Description)
+	}
+}
@@ -0,0 +1,48 @@
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/ercorn/gator/internal/urlnorm"
+)
+
+// Key identifies the item within its feed: the guid/id given by the publisher,
+// otherwise the normalized link, otherwise a hash of the title and description
+func (i Item) Key() string {
+	if guid := strings.TrimSpace(i.GUID); guid != "" {
+		return guid
+	}
+	if link := normalizeLink(i.Link); link != "" {
+		return link
+	}
+	sum := sha256.Sum256([]byte(i.Title + "\n" + i.Description))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ContentHash fingerprints the parts of the item a publisher may edit, a
+// changed hash for a known key means the stored post is out of date. The full
+// content only counts when there is some, so items without it keep the hash
+// they had before content was captured.
+func (i Item) ContentHash() string {
+	fingerprint := i.Title + "\x00" + i.Link + "\x00" + i.Description
+	if i.Content != "" {
+		fingerprint += "\x00" + i.Content
+	}
+	sum := sha256.Sum256([]byte(fingerprint))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeLink canonicalizes the link with urlnorm, links that it can't
+// handle are returned trimmed
+func normalizeLink(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	if normalized, err := urlnorm.Normalize(link); err == nil {
+		return normalized
+	}
+	return link
+}
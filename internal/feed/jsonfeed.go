@@ -0,0 +1,191 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const jsonFeedVersionPrefix = "https://jsonfeed.org/version/"
+
+// jsonFeedID accepts both string and numeric ids, JSON Feed 1.0 publishers
+// are not consistent about it even though the spec asks for strings
+type jsonFeedID string
+
+func (id *jsonFeedID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = jsonFeedID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = jsonFeedID(n.String())
+	return nil
+}
+
+type jsonFeedAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+type jsonFeedItem struct {
+	ID            jsonFeedID           `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Attachments   []jsonFeedAttachment `json:"attachments"`
+	Authors       []atomPerson         `json:"authors"`
+	Author        *atomPerson          `json:"author"`
+	Tags          []string             `json:"tags"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Language    string         `json:"language"`
+	Icon        string         `json:"icon"`
+	Favicon     string         `json:"favicon"`
+	Authors     []atomPerson   `json:"authors"`
+	Author      *atomPerson    `json:"author"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+// JSONFeedParser handles JSON Feed 1.0 and 1.1
+type JSONFeedParser struct{}
+
+func init() {
+	Register(JSONFeedParser{})
+}
+
+func (JSONFeedParser) Name() string {
+	return "json feed"
+}
+
+// Detect only claims json carrying a JSON Feed version, any other json (like
+// an API error page served with a 200) is not a feed
+func (JSONFeedParser) Detect(s Sniff) bool {
+	return strings.HasPrefix(s.JSONVersion, jsonFeedVersionPrefix)
+}
+
+func (JSONFeedParser) Parse(body []byte) (*Feed, error) {
+	doc := jsonFeed{}
+	err := json.Unmarshal(body, &doc)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(doc.Version, jsonFeedVersionPrefix) {
+		return nil, fmt.Errorf("not a JSON Feed: version %q", doc.Version)
+	}
+
+	//json strings carry no xml escaping, so unlike the xml formats the
+	//fields are used as they are
+	f := &Feed{
+		Title:       doc.Title,
+		Link:        doc.HomePageURL,
+		Description: doc.Description,
+		Language:    doc.Language,
+		Image:       doc.Icon,
+	}
+	if f.Image == "" {
+		f.Image = doc.Favicon
+	}
+	for _, item := range doc.Items {
+		description := item.Summary
+		if description == "" {
+			description = item.ContentHTML
+		}
+		if description == "" {
+			description = item.ContentText
+		}
+
+		content := item.ContentHTML
+		if content == "" {
+			content = item.ContentText
+		}
+
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		title := item.Title
+		if title == "" {
+			//title is optional in JSON Feed (microblog posts), use the
+			//start of the text content instead
+			title = truncate(strings.TrimSpace(item.ContentText), 80)
+		}
+
+		date := item.DatePublished
+		if date == "" {
+			date = item.DateModified
+		}
+		published, _ := ParseDate(date)
+
+		enclosures := []Enclosure{}
+		for _, attachment := range item.Attachments {
+			if attachment.URL == "" {
+				continue
+			}
+			enclosures = append(enclosures, Enclosure{
+				URL:      attachment.URL,
+				Type:     attachment.MimeType,
+				Length:   max(attachment.SizeInBytes, 0),
+				Duration: time.Duration(max(attachment.DurationInSeconds, 0) * float64(time.Second)).Round(time.Second),
+			})
+		}
+
+		//items without authors inherit the feed's
+		authors := jsonFeedAuthors(item.Authors, item.Author)
+		if len(authors) == 0 {
+			authors = jsonFeedAuthors(doc.Authors, doc.Author)
+		}
+
+		f.Items = append(f.Items, Item{
+			GUID:        string(item.ID),
+			Title:       title,
+			Link:        link,
+			Description: description,
+			Content:     content,
+			Published:   published,
+			Enclosures:  enclosures,
+			Authors:     authors,
+			Categories:  uniqueNames(item.Tags),
+		})
+	}
+	return f, nil
+}
+
+// jsonFeedAuthors merges authors with author, the JSON Feed 1.0 form that
+// 1.1 replaced
+func jsonFeedAuthors(authors []atomPerson, author *atomPerson) []string {
+	if author != nil {
+		authors = append(authors, *author)
+	}
+	names := []string{}
+	for _, a := range authors {
+		names = append(names, a.Name)
+	}
+	return uniqueNames(names)
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
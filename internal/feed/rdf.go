@@ -0,0 +1,83 @@
+package feed
+
+import (
+	"strings"
+)
+
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// rdfItem is an RSS 1.0 item, dates come from Dublin Core instead of pubDate
+type rdfItem struct {
+	About       string   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects    []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+// rdfFeed is an RSS 1.0 document, unlike RSS 2.0 the items are siblings of
+// the channel element rather than children of it
+type rdfFeed struct {
+	Channel struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Language    string `xml:"http://purl.org/dc/elements/1.1/ language"`
+		rssScheduleElements
+	} `xml:"channel"`
+	Image struct {
+		URL string `xml:"url"`
+	} `xml:"image"`
+	Item []rdfItem `xml:"item"`
+}
+
+// RDFParser handles RSS 1.0 (rdf:RDF) documents
+type RDFParser struct{}
+
+func init() {
+	Register(RDFParser{})
+}
+
+func (RDFParser) Name() string {
+	return "rdf"
+}
+
+func (RDFParser) Detect(s Sniff) bool {
+	return s.Root.Local == "RDF" && s.Root.Space == rdfNamespace
+}
+
+func (RDFParser) Parse(body []byte) (*Feed, error) {
+	doc := rdfFeed{}
+	err := newXMLDecoder(body).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Feed{
+		Title:       doc.Channel.Title,
+		Link:        strings.TrimSpace(doc.Channel.Link),
+		Description: doc.Channel.Description,
+		Language:    strings.TrimSpace(doc.Channel.Language),
+		Image:       strings.TrimSpace(doc.Image.URL),
+		Schedule:    doc.Channel.hints(),
+	}
+	for _, item := range doc.Item {
+		published, _ := ParseDate(item.Date)
+		f.Items = append(f.Items, Item{
+			GUID:        item.About,
+			Title:       item.Title,
+			Link:        strings.TrimSpace(item.Link),
+			Description: item.Description,
+			Content:     strings.TrimSpace(item.Content),
+			Published:   published,
+			Authors:     uniqueNames(item.Creators),
+			Categories:  uniqueNames(item.Subjects),
+		})
+	}
+
+	unescapeHTML(f)
+	return f, nil
+}
@@ -0,0 +1,114 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+)
+
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// Sniff is what is known about a response before it is parsed: the media type
+// from the Content-Type header and the root element of the body. Root is left
+// empty for bodies that are not xml, JSONVersion holds the top level
+// "version" member of json bodies. Servers often send the wrong content type,
+// so the body decides and MediaType only settles what the body leaves open.
+type Sniff struct {
+	MediaType   string
+	Root        xml.Name
+	JSON        bool
+	JSONVersion string
+}
+
+// Parser is implemented by every supported feed format
+type Parser interface {
+	// Name identifies the format in logs and errors
+	Name() string
+	// Detect reports whether the sniffed document is in this format
+	Detect(s Sniff) bool
+	// Parse decodes the document into the normalized model
+	Parse(body []byte) (*Feed, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	parsers    []Parser
+)
+
+// Register adds a format to the registry. Formats are tried in registration
+// order, so a parser should only claim documents it is sure about.
+func Register(p Parser) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	parsers = append(parsers, p)
+}
+
+// SniffDocument inspects the content type and the start of the body
+func SniffDocument(contentType string, body []byte) Sniff {
+	s := Sniff{}
+	if media_type, _, err := mime.ParseMediaType(contentType); err == nil {
+		s.MediaType = strings.ToLower(media_type)
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		s.JSON = true
+		doc := struct {
+			Version string `json:"version"`
+		}{}
+		if json.Unmarshal(trimmed, &doc) == nil {
+			s.JSONVersion = doc.Version
+		}
+		return s
+	}
+
+	decoder := newXMLDecoder(trimmed)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return s
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			s.Root = start.Name
+			return s
+		}
+	}
+}
+
+// Detect returns the registered parser that claims the document, the body
+// must be UTF-8 already
+func Detect(contentType string, body []byte) (Parser, error) {
+	s := SniffDocument(contentType, body)
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, p := range parsers {
+		if p.Detect(s) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w (content type %q, root element %q)", ErrUnknownFormat, s.MediaType, s.Root.Local)
+}
+
+// Parse transcodes the document to UTF-8, detects its format and decodes it
+func Parse(contentType string, body []byte) (*Feed, error) {
+	body, err := toUTF8(contentType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := Detect(contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	f, err := p.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s feed: %w", p.Name(), err)
+	}
+	return f, nil
+}
@@ -0,0 +1,170 @@
+package feed
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		contentType string
+		want        string
+		title       string
+		link        string
+	}{
+		{"rss.xml", "application/rss+xml", "rss", "First post", "https://example.com/first"},
+		{"atom.xml", "application/atom+xml", "atom", "First entry", "https://example.com/first"},
+		{"rdf.xml", "application/rdf+xml", "rdf", "First item", "https://example.com/first"},
+		{"jsonfeed.json", "application/feed+json", "json feed", "First item", "https://example.com/first"},
+		//servers often get the content type wrong, the body decides
+		{"rss.xml", "text/html; charset=utf-8", "rss", "First post", "https://example.com/first"},
+		{"atom.xml", "text/xml", "atom", "First entry", "https://example.com/first"},
+		{"jsonfeed.json", "text/plain", "json feed", "First item", "https://example.com/first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture+" as "+tt.contentType, func(t *testing.T) {
+			body := readFixture(t, tt.fixture)
+			p, err := Detect(tt.contentType, body)
+			if err != nil {
+				t.Fatalf("Detect: %v", err)
+			}
+			if p.Name() != tt.want {
+				t.Fatalf("Detect = %s, want %s", p.Name(), tt.want)
+			}
+
+			f, err := Parse(tt.contentType, body)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(f.Items) != 1 {
+				t.Fatalf("got %d items, want 1", len(f.Items))
+			}
+			item := f.Items[0]
+			if item.Title != tt.title || item.Link != tt.link {
+				t.Errorf("item = %q %q, want %q %q", item.Title, item.Link, tt.title, tt.link)
+			}
+			want := time.Date(2024, time.March, 5, 14, 30, 15, 0, time.UTC)
+			if !item.Published.Equal(want) {
+				t.Errorf("Published = %s, want %s", item.Published, want)
+			}
+		})
+	}
+}
+
+func TestDetectUnknown(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"html page", "text/html", `<!DOCTYPE html><html><head><title>Blog</title></head><body><p>hi</p></body></html>`},
+		{"json api error", "application/json", `{"error": "not found", "status": 404}`},
+		{"json with foreign version", "application/json", `{"version": "2.0", "items": []}`},
+		{"feed content type without a feed", "application/feed+json", `{"title": "x", "items": []}`},
+		{"unknown root element", "application/xml", `<?xml version="1.0"?><urlset><url><loc>https://example.com/</loc></url></urlset>`},
+		{"atom root in the wrong namespace", "application/xml", `<feed><entry><title>x</title></entry></feed>`},
+		{"empty body", "application/rss+xml", ``},
+		{"plain text", "text/plain", `just some text`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Detect(tt.contentType, []byte(tt.body))
+			if !errors.Is(err, ErrUnknownFormat) {
+				t.Fatalf("Detect = %v, %v, want ErrUnknownFormat", p, err)
+			}
+			_, err = Parse(tt.contentType, []byte(tt.body))
+			if !errors.Is(err, ErrUnknownFormat) {
+				t.Errorf("Parse error = %v, want ErrUnknownFormat", err)
+			}
+		})
+	}
+}
+
+func TestJSONFeedParseRequiresVersion(t *testing.T) {
+	_, err := JSONFeedParser{}.Parse([]byte(`{"title": "x", "items": [{"id": "1"}]}`))
+	if err == nil {
+		t.Error("Parse accepted json without a JSON Feed version")
+	}
+}
+
+func TestRSSItemAtomLink(t *testing.T) {
+	body := `<rss xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>x</title>
+		<item><title>a</title><link>https://example.com/a</link><atom:link rel="self" href=""/></item>
+	</channel></rss>`
+	f, err := Parse("application/rss+xml", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Items[0].Link != "https://example.com/a" {
+		t.Errorf("Link = %q, want https://example.com/a", f.Items[0].Link)
+	}
+}
+
+func TestRSSNamespacedTitles(t *testing.T) {
+	body := `<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:media="http://search.yahoo.com/mrss/">
+		<channel><title>The Show</title><itunes:title>Show</itunes:title><description>About the show</description>
+		<item><title>Ep 5: Foo</title><itunes:title>Foo</itunes:title><media:title>Foo!</media:title>
+			<description>Show notes</description><media:description>Thumbnail caption</media:description></item>
+		<item><itunes:title>Only itunes</itunes:title><media:description>Only media</media:description></item>
+		</channel></rss>`
+	f, err := Parse("application/rss+xml", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Title != "The Show" || f.Description != "About the show" {
+		t.Errorf("feed = %q %q, want The Show, About the show", f.Title, f.Description)
+	}
+	if len(f.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(f.Items))
+	}
+	if item := f.Items[0]; item.Title != "Ep 5: Foo" || item.Description != "Show notes" {
+		t.Errorf("item = %q %q, want Ep 5: Foo, Show notes", item.Title, item.Description)
+	}
+	//the namespaced elements stand in for missing plain ones
+	if item := f.Items[1]; item.Title != "Only itunes" || item.Description != "Only media" {
+		t.Errorf("item = %q %q, want Only itunes, Only media", item.Title, item.Description)
+	}
+}
+
+func TestRDFUnescapesText(t *testing.T) {
+	body := `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+		<channel><title>Tom &amp;amp; Jerry</title></channel>
+		<item><title>Don&amp;#8217;t panic</title><description>&amp;ldquo;quoted&amp;rdquo;</description></item>
+	</rdf:RDF>`
+	f, err := Parse("application/rdf+xml", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Title != "Tom & Jerry" {
+		t.Errorf("Title = %q, want Tom & Jerry", f.Title)
+	}
+	if item := f.Items[0]; item.Title != "Don’t panic" || item.Description != "“quoted”" {
+		t.Errorf("item = %q %q", item.Title, item.Description)
+	}
+}
+
+func TestDetectAtomWithoutNamespace(t *testing.T) {
+	body := `<feed><title>x</title><entry><id>1</id><title>First</title><link href="https://example.com/first"/></entry></feed>`
+	f, err := Parse("application/atom+xml; charset=utf-8", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Items) != 1 || f.Items[0].Link != "https://example.com/first" {
+		t.Errorf("items = %+v, want the first entry", f.Items)
+	}
+}
@@ -0,0 +1,130 @@
+package feed
+
+import (
+	"strings"
+)
+
+type rssItem struct {
+	//as on the channel, namespaced elements have to be claimed before the
+	//plain fields see them: itunes:title is often shorter than <title> and
+	//media:description would replace the description
+	rssTitleElements
+	Title       string     `xml:"title"`
+	AtomLinks   []atomLink `xml:"http://www.w3.org/2005/Atom link"`
+	Link        string     `xml:"link"`
+	Description string     `xml:"description"`
+	Content     string     `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string     `xml:"pubDate"`
+	GUID        string     `xml:"guid"`
+	//dc:creator holds a name where <author> is meant to be an email address,
+	//itunes:author also lands in Authors
+	Creators   []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Authors    []string `xml:"author"`
+	Categories []string `xml:"category"`
+	mediaElements
+}
+
+type rssFeed struct {
+	Channel struct {
+		//fields with a namespace are listed before the plain ones they would
+		//otherwise be matched into, atom:link (rel="self") would blank Link
+		rssTitleElements
+		Title       string     `xml:"title"`
+		AtomLinks   []atomLink `xml:"http://www.w3.org/2005/Atom link"`
+		Link        string     `xml:"link"`
+		Description string     `xml:"description"`
+		Language    string     `xml:"language"`
+		ITunesImage struct {
+			Href string `xml:"href,attr"`
+		} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Image struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		Item []rssItem `xml:"item"`
+		rssScheduleElements
+	} `xml:"channel"`
+}
+
+// rssTitleElements are the podcast and Media RSS elements named like <title>
+// and <description>, they are only used when those are missing
+type rssTitleElements struct {
+	ITunesTitle      string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd title"`
+	MediaTitle       string `xml:"http://search.yahoo.com/mrss/ title"`
+	MediaDescription string `xml:"http://search.yahoo.com/mrss/ description"`
+}
+
+func (e rssTitleElements) title(title string) string {
+	for _, t := range []string{title, e.ITunesTitle, e.MediaTitle} {
+		if strings.TrimSpace(t) != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+func (e rssTitleElements) description(description string) string {
+	if strings.TrimSpace(description) != "" {
+		return description
+	}
+	return e.MediaDescription
+}
+
+func (item rssItem) authors() []string {
+	authors := []string{}
+	for _, author := range item.Authors {
+		authors = append(authors, rssAuthorName(author))
+	}
+	return uniqueNames(item.Creators, authors)
+}
+
+// RSSParser handles RSS 2.0 (and the 0.9x versions sharing its layout)
+type RSSParser struct{}
+
+func init() {
+	Register(RSSParser{})
+}
+
+func (RSSParser) Name() string {
+	return "rss"
+}
+
+func (RSSParser) Detect(s Sniff) bool {
+	return s.Root.Local == "rss"
+}
+
+func (RSSParser) Parse(body []byte) (*Feed, error) {
+	doc := rssFeed{}
+	err := newXMLDecoder(body).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Feed{
+		Title:       doc.Channel.title(doc.Channel.Title),
+		Link:        doc.Channel.Link,
+		Description: doc.Channel.description(doc.Channel.Description),
+		Language:    strings.TrimSpace(doc.Channel.Language),
+		Image:       strings.TrimSpace(doc.Channel.Image.URL),
+		Schedule:    doc.Channel.hints(),
+	}
+	if f.Image == "" {
+		f.Image = strings.TrimSpace(doc.Channel.ITunesImage.Href)
+	}
+	for _, item := range doc.Channel.Item {
+		published, _ := ParseDate(item.PubDate)
+		f.Items = append(f.Items, Item{
+			GUID:        strings.TrimSpace(item.GUID),
+			Title:       item.title(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Description: item.description(item.Description),
+			Content:     strings.TrimSpace(item.Content),
+			Published:   published,
+			Enclosures:  item.enclosures(),
+			Authors:     item.authors(),
+			Categories:  uniqueNames(item.Categories),
+		})
+	}
+
+	unescapeHTML(f)
+	return f, nil
+}
@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleHints are the publisher's polling instructions found in a feed
+type ScheduleHints struct {
+	// TTL is the RSS <ttl>, how long the channel may be cached
+	TTL time.Duration `json:"ttl,omitempty"`
+	// UpdatePeriod is sy:updatePeriod divided by sy:updateFrequency
+	UpdatePeriod time.Duration `json:"update_period,omitempty"`
+	// SkipHours are the GMT hours the feed should not be fetched in
+	SkipHours []int `json:"skip_hours,omitempty"`
+	// SkipDays are the days the feed should not be fetched on
+	SkipDays []time.Weekday `json:"skip_days,omitempty"`
+}
+
+// MinInterval is the shortest polling interval the publisher asks for
+func (h ScheduleHints) MinInterval() time.Duration {
+	return max(h.TTL, h.UpdatePeriod)
+}
+
+// Skipped reports whether t falls in one of the skipped hours or days
+func (h ScheduleHints) Skipped(t time.Time) bool {
+	t = t.UTC()
+	for _, hour := range h.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+	for _, day := range h.SkipDays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// rssScheduleElements are the channel level scheduling elements shared by
+// RSS 2.0 (ttl, skipHours, skipDays) and the syndication module (sy:*)
+type rssScheduleElements struct {
+	TTL             string   `xml:"ttl"`
+	SkipHours       []string `xml:"skipHours>hour"`
+	SkipDays        []string `xml:"skipDays>day"`
+	UpdatePeriod    string   `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string   `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+var syndicationPeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// hints converts the raw elements, values that don't parse are ignored
+func (e rssScheduleElements) hints() ScheduleHints {
+	h := ScheduleHints{}
+	if minutes, err := strconv.Atoi(strings.TrimSpace(e.TTL)); err == nil && minutes > 0 {
+		h.TTL = time.Duration(minutes) * time.Minute
+	}
+
+	if period, ok := syndicationPeriods[strings.ToLower(strings.TrimSpace(e.UpdatePeriod))]; ok {
+		frequency, err := strconv.Atoi(strings.TrimSpace(e.UpdateFrequency))
+		if err != nil || frequency < 1 {
+			frequency = 1
+		}
+		h.UpdatePeriod = period / time.Duration(frequency)
+	}
+
+	for _, value := range e.SkipHours {
+		hour, err := strconv.Atoi(strings.TrimSpace(value))
+		//RSS 2.0 allows 0-23, some feeds use 24 for midnight
+		if err == nil && hour >= 0 && hour <= 24 {
+			h.SkipHours = append(h.SkipHours, hour%24)
+		}
+	}
+	for _, value := range e.SkipDays {
+		if day, ok := weekdays[strings.ToLower(strings.TrimSpace(value))]; ok {
+			h.SkipDays = append(h.SkipDays, day)
+		}
+	}
+	return h
+}
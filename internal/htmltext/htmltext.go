@@ -0,0 +1,384 @@
+// Package htmltext renders the html of post descriptions and content as plain
+// text for the terminal: wrapped to a width, with lists, quotes and
+// preformatted blocks laid out and links turned into numbered footnotes.
+package htmltext
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// minTextWidth keeps deeply nested text readable on narrow terminals
+const minTextWidth = 20
+
+// skipped elements are dropped along with everything inside them
+var skipped = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Head:     true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Svg:      true,
+	atom.Form:     true,
+	atom.Button:   true,
+}
+
+// blockElements start a new paragraph
+var blockElements = map[atom.Atom]bool{
+	atom.P:          true,
+	atom.Div:        true,
+	atom.Section:    true,
+	atom.Article:    true,
+	atom.Header:     true,
+	atom.Footer:     true,
+	atom.Aside:      true,
+	atom.Nav:        true,
+	atom.Main:       true,
+	atom.Figure:     true,
+	atom.Figcaption: true,
+	atom.Table:      true,
+	atom.Tr:         true,
+	atom.Dl:         true,
+	atom.Dt:         true,
+	atom.Dd:         true,
+	atom.Details:    true,
+	atom.Summary:    true,
+	atom.Address:    true,
+}
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1,
+	atom.H2: 2,
+	atom.H3: 3,
+	atom.H4: 4,
+	atom.H5: 5,
+	atom.H6: 6,
+}
+
+// block is a paragraph of output, first is the prefix of its first line and
+// rest the prefix of the lines it wraps onto
+type block struct {
+	first string
+	rest  string
+	text  string
+	pre   bool
+	// listed blocks follow each other without a blank line
+	listed bool
+}
+
+type list struct {
+	ordered bool
+	next    int
+}
+
+type renderer struct {
+	blocks []block
+	inline strings.Builder
+	// prefixes are the quote markers and list indentation around the text
+	prefixes []string
+	// marker is the bullet or number of a list item that has not been
+	// written yet, it replaces the item's indentation on its first line
+	marker string
+	lists  []list
+	links  []string
+	// footnotes maps a link to its footnote number
+	footnotes map[string]int
+	base      *url.URL
+}
+
+// Render converts an html fragment to text wrapped at width columns. Links are
+// numbered in the text and listed after it, relative ones resolved against
+// base (the page the fragment comes from) when it is given.
+func Render(src, base string, width int) string {
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		//not html after all, show it as it is
+		return StripControl(wrap(collapseSpace(src), "", "", width))
+	}
+
+	r := &renderer{footnotes: map[string]int{}}
+	if u, err := url.Parse(base); err == nil && u.IsAbs() {
+		r.base = u
+	}
+	for _, n := range nodes {
+		r.walk(n)
+	}
+	r.flush()
+
+	var out strings.Builder
+	for i, b := range r.blocks {
+		switch {
+		case i == 0:
+		case b.listed && r.blocks[i-1].listed:
+			out.WriteString("\n")
+		default:
+			out.WriteString("\n\n")
+		}
+		if b.pre {
+			out.WriteString(prefixLines(b.text, b.first, b.rest))
+		} else {
+			out.WriteString(wrap(b.text, b.first, b.rest, width))
+		}
+	}
+
+	if len(r.links) > 0 {
+		out.WriteString("\n")
+		for i, link := range r.links {
+			fmt.Fprintf(&out, "\n[%d] %s", i+1, link)
+		}
+	}
+	return StripControl(out.String())
+}
+
+// StripControl removes the C0 and C1 control characters other than newline
+// and tab, so text from a feed can't send escape sequences to the terminal
+func StripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.inline.WriteString(collapseSpace(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		r.children(n)
+		return
+	}
+
+	if skipped[n.DataAtom] {
+		return
+	}
+
+	if level, ok := headingLevels[n.DataAtom]; ok {
+		r.flush()
+		r.inline.WriteString(strings.Repeat("#", level) + " ")
+		r.children(n)
+		r.flush()
+		return
+	}
+	if blockElements[n.DataAtom] {
+		r.flush()
+		r.children(n)
+		r.flush()
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		r.inline.WriteString("\n")
+	case atom.Hr:
+		r.flush()
+		r.inline.WriteString("----")
+		r.flush()
+	case atom.Td, atom.Th:
+		r.inline.WriteString(" ")
+		r.children(n)
+		r.inline.WriteString(" ")
+	case atom.Img:
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			r.inline.WriteString("[image: " + collapseSpace(alt) + "]")
+		}
+	case atom.A:
+		r.children(n)
+		if number, ok := r.footnote(attr(n, "href")); ok {
+			r.inline.WriteString("[" + strconv.Itoa(number) + "]")
+		}
+	case atom.Pre:
+		r.flush()
+		r.blocks = append(r.blocks, block{
+			first:  r.firstPrefix(),
+			rest:   strings.Join(r.prefixes, ""),
+			text:   strings.Trim(textContent(n), "\n"),
+			pre:    true,
+			listed: len(r.lists) > 0,
+		})
+		r.marker = ""
+	case atom.Blockquote:
+		r.flush()
+		r.prefixes = append(r.prefixes, "> ")
+		r.children(n)
+		r.flush()
+		r.prefixes = r.prefixes[:len(r.prefixes)-1]
+	case atom.Ul, atom.Ol:
+		r.flush()
+		r.lists = append(r.lists, list{ordered: n.DataAtom == atom.Ol, next: 1})
+		if start, err := strconv.Atoi(attr(n, "start")); err == nil && n.DataAtom == atom.Ol {
+			r.lists[len(r.lists)-1].next = start
+		}
+		r.children(n)
+		r.flush()
+		r.lists = r.lists[:len(r.lists)-1]
+	case atom.Li:
+		r.flush()
+		marker := "* "
+		if len(r.lists) > 0 && r.lists[len(r.lists)-1].ordered {
+			current := &r.lists[len(r.lists)-1]
+			marker = strconv.Itoa(current.next) + ". "
+			current.next++
+		}
+		r.marker = marker
+		r.prefixes = append(r.prefixes, strings.Repeat(" ", len(marker)))
+		r.children(n)
+		r.flush()
+		r.prefixes = r.prefixes[:len(r.prefixes)-1]
+		r.marker = ""
+	default:
+		r.children(n)
+	}
+}
+
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.walk(c)
+	}
+}
+
+// flush ends the current paragraph
+func (r *renderer) flush() {
+	text := r.inline.String()
+	r.inline.Reset()
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	r.blocks = append(r.blocks, block{
+		first:  r.firstPrefix(),
+		rest:   strings.Join(r.prefixes, ""),
+		text:   text,
+		listed: len(r.lists) > 0,
+	})
+	r.marker = ""
+}
+
+// firstPrefix is the prefix with a pending list marker in place of the list
+// item's indentation
+func (r *renderer) firstPrefix() string {
+	if r.marker == "" || len(r.prefixes) == 0 {
+		return strings.Join(r.prefixes, "")
+	}
+	return strings.Join(r.prefixes[:len(r.prefixes)-1], "") + r.marker
+}
+
+// footnote numbers a link, the same link always gets the same number. Links
+// that lead nowhere outside the page get none.
+func (r *renderer) footnote(href string) (int, bool) {
+	href = strings.TrimSpace(href)
+	lower := strings.ToLower(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(lower, "javascript:") {
+		return 0, false
+	}
+	if r.base != nil {
+		if resolved, err := r.base.Parse(href); err == nil {
+			href = resolved.String()
+		}
+	}
+	if number, ok := r.footnotes[href]; ok {
+		return number, true
+	}
+	r.links = append(r.links, href)
+	r.footnotes[href] = len(r.links)
+	return len(r.links), true
+}
+
+// wrap breaks the text into lines of at most width columns (a single word
+// longer than that gets a line of its own), keeping hard line breaks
+func wrap(text, first, rest string, width int) string {
+	lines := []string{}
+	prefix := first
+	for _, segment := range strings.Split(text, "\n") {
+		words := strings.Fields(segment)
+		if len(words) == 0 {
+			continue
+		}
+		available := max(width-utf8.RuneCountInString(rest), minTextWidth)
+		line := ""
+		for _, word := range words {
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= available:
+				line += " " + word
+			default:
+				lines = append(lines, prefix+line)
+				prefix = rest
+				line = word
+			}
+		}
+		lines = append(lines, prefix+line)
+		prefix = rest
+	}
+	return strings.Join(lines, "\n")
+}
+
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = first + lines[i]
+		} else {
+			lines[i] = rest + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// collapseSpace turns every run of whitespace into a single space, the way a
+// browser lays out text
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, c := range s {
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		b.WriteRune(c)
+		space = false
+	}
+	return b.String()
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,37 @@
+package htmltext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderStripsControlCharacters(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"csi in text", "<p>plain \x1b[31mred\x1b[0m text</p>"},
+		{"osc in link", `<p><a href="https://example.com/` + "\x1b]0;title\x07" + `">link</a></p>`},
+		{"c1 csi", "<p>clear \u009b2J screen</p>"},
+		{"not html", "\x1b[2J\x1b[H"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.src, "", 80)
+			if strings.ContainsFunc(got, func(r rune) bool {
+				return r != '\n' && r != '\t' && (r < 0x20 || (r >= 0x7f && r <= 0x9f))
+			}) {
+				t.Errorf("Render(%q) = %q, contains control characters", tt.src, got)
+			}
+		})
+	}
+}
+
+func TestStripControl(t *testing.T) {
+	got := StripControl("a\x1b[1mb\r\n\tc\u0085d\x7fe")
+	want := "a[1mb\n\tcde"
+	if got != want {
+		t.Errorf("StripControl = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,330 @@
+// Package sanitize cleans the html that feeds publish before it is stored, so
+// that posts can be shown in a browser without running the publisher's (or an
+// attacker's) scripts. It works from an allowlist: elements and attributes
+// not listed are dropped, only http(s) and relative urls are kept, iframes
+// only survive for trusted video hosts and tracking pixels are removed.
+package sanitize
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowed lists the elements kept and the attributes each of them may carry
+var allowed = map[atom.Atom][]string{
+	atom.A:          {"href", "title"},
+	atom.Abbr:       {"title"},
+	atom.Audio:      {"src", "controls"},
+	atom.B:          nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Caption:    nil,
+	atom.Cite:       nil,
+	atom.Code:       nil,
+	atom.Dd:         nil,
+	atom.Del:        nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Iframe:     {"src", "width", "height", "allowfullscreen"},
+	atom.Img:        {"src", "alt", "title", "width", "height"},
+	atom.Ins:        nil,
+	atom.Li:         nil,
+	atom.Mark:       nil,
+	atom.Ol:         {"start"},
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.S:          nil,
+	atom.Small:      nil,
+	atom.Source:     {"src", "type"},
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Thead:      nil,
+	atom.Tr:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+	atom.Video:      {"src", "poster", "controls", "width", "height"},
+}
+
+// dropped elements are removed with everything inside them, other elements
+// that aren't allowed are unwrapped and keep their text
+var dropped = map[atom.Atom]bool{
+	atom.Applet:   true,
+	atom.Base:     true,
+	atom.Button:   true,
+	atom.Embed:    true,
+	atom.Form:     true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Head:     true,
+	atom.Input:    true,
+	atom.Link:     true,
+	atom.Math:     true,
+	atom.Meta:     true,
+	atom.Noscript: true,
+	atom.Object:   true,
+	atom.Script:   true,
+	atom.Select:   true,
+	atom.Style:    true,
+	atom.Svg:      true,
+	atom.Template: true,
+	atom.Textarea: true,
+	atom.Title:    true,
+}
+
+var voidElements = map[atom.Atom]bool{
+	atom.Br:     true,
+	atom.Hr:     true,
+	atom.Img:    true,
+	atom.Source: true,
+}
+
+// urlAttributes hold a url that has to pass the scheme check
+var urlAttributes = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"poster": true,
+	"cite":   true,
+}
+
+// DefaultIframeHosts are the video players whose embeds are kept
+var DefaultIframeHosts = []string{
+	"www.youtube.com",
+	"youtube.com",
+	"www.youtube-nocookie.com",
+	"player.vimeo.com",
+	"w.soundcloud.com",
+	"open.spotify.com",
+	"embed.podcasts.apple.com",
+}
+
+// trackerHosts only ever serve tracking pixels and web bugs
+var trackerHosts = []string{
+	"feeds.feedburner.com",
+	"feedproxy.google.com",
+	"pixel.wp.com",
+	"stats.wordpress.com",
+	"www.google-analytics.com",
+	"doubleclick.net",
+	"pixel.quantserve.com",
+	"feedpress.me",
+	"pixel.feedblitz.com",
+	"counters.gigya.com",
+}
+
+// Policy sanitizes html, the zero value is not usable, see NewPolicy
+type Policy struct {
+	iframeHosts map[string]bool
+}
+
+// NewPolicy returns a policy that keeps iframes from DefaultIframeHosts and
+// the given extra hosts
+func NewPolicy(extraIframeHosts []string) *Policy {
+	p := &Policy{iframeHosts: map[string]bool{}}
+	for _, hosts := range [][]string{DefaultIframeHosts, extraIframeHosts} {
+		for _, host := range hosts {
+			p.iframeHosts[strings.ToLower(strings.TrimSpace(host))] = true
+		}
+	}
+	return p
+}
+
+// HTML returns the sanitized version of an html fragment
+func (p *Policy) HTML(src string) string {
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		//unparseable input can't be trusted to be anything but text
+		return html.EscapeString(src)
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		p.write(&b, n)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (p *Policy) write(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		//comments, doctypes
+		return
+	}
+
+	if dropped[n.DataAtom] || (n.Namespace != "" && n.Namespace != "html") {
+		return
+	}
+	attrs, ok := allowed[n.DataAtom]
+	if !ok {
+		p.children(b, n)
+		return
+	}
+	if n.DataAtom == atom.Img && isTrackingPixel(n) {
+		return
+	}
+
+	kept := p.attributes(n, attrs)
+	if n.DataAtom == atom.Iframe && !p.trustedIframe(kept) {
+		return
+	}
+	if (n.DataAtom == atom.Img || n.DataAtom == atom.Source) && !hasAttr(kept, "src") {
+		return
+	}
+
+	b.WriteString("<" + n.Data)
+	for _, a := range kept {
+		b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+	}
+	if n.DataAtom == atom.A && hasAttr(kept, "href") {
+		//links leave the page, don't give the target a handle on it
+		b.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	b.WriteString(">")
+	if voidElements[n.DataAtom] {
+		return
+	}
+	p.children(b, n)
+	b.WriteString("</" + n.Data + ">")
+}
+
+func (p *Policy) children(b *strings.Builder, n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.write(b, c)
+	}
+}
+
+// attributes keeps the allowed attributes of the element, dropping urls with
+// a scheme other than http(s) (or mailto for links)
+func (p *Policy) attributes(n *html.Node, allowedAttrs []string) []html.Attribute {
+	kept := []html.Attribute{}
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !slices.Contains(allowedAttrs, a.Key) {
+			continue
+		}
+		if urlAttributes[a.Key] {
+			safe, ok := safeURL(a.Val, n.DataAtom == atom.A)
+			if !ok {
+				continue
+			}
+			a.Val = safe
+		}
+		kept = append(kept, a)
+	}
+	return kept
+}
+
+func (p *Policy) trustedIframe(attrs []html.Attribute) bool {
+	for _, a := range attrs {
+		if a.Key != "src" {
+			continue
+		}
+		u, err := url.Parse(a.Val)
+		return err == nil && u.Scheme == "https" && p.iframeHosts[strings.ToLower(u.Hostname())]
+	}
+	return false
+}
+
+// safeURL checks the scheme of a url attribute. Browsers ignore whitespace
+// and control characters in a scheme, so "java\tscript:" counts as
+// javascript too.
+func safeURL(value string, link bool) (string, bool) {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return strings.TrimSpace(value), true
+	case "mailto":
+		return strings.TrimSpace(value), link
+	}
+	return "", false
+}
+
+// isTrackingPixel spots the 1x1 (or hidden) images and known web bug hosts
+// that feeds use to count readers
+func isTrackingPixel(n *html.Node) bool {
+	width, height := attrValue(n, "width"), attrValue(n, "height")
+	if tiny(width) && tiny(height) {
+		return true
+	}
+	style := strings.ToLower(strings.ReplaceAll(attrValue(n, "style"), " ", ""))
+	if strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+		return true
+	}
+
+	u, err := url.Parse(strings.TrimSpace(attrValue(n, "src")))
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, tracker := range trackerHosts {
+		if host == tracker || strings.HasSuffix(host, "."+tracker) {
+			return true
+		}
+	}
+	return false
+}
+
+func tiny(dimension string) bool {
+	dimension = strings.TrimSuffix(strings.TrimSpace(dimension), "px")
+	return dimension == "0" || dimension == "1"
+}
+
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(attrs []html.Attribute, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package sanitize
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ercorn/gator/internal/feed"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		//url schemes
+		{"javascript link", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"mixed case scheme", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a>x</a>`},
+		{"tab in scheme", "<a href=\"java\tscript:alert(1)\">x</a>", `<a>x</a>`},
+		{"encoded tab in scheme", `<a href="java&#x09;script:alert(1)">x</a>`, `<a>x</a>`},
+		{"newline in scheme", "<a href=\"java\nscript:alert(1)\">x</a>", `<a>x</a>`},
+		{"entity encoded scheme", `<a href="&#x6A;avascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"leading control character", "<a href=\" \x01javascript:alert(1)\">x</a>", `<a>x</a>`},
+		{"vbscript", `<a href="vbscript:msgbox(1)">x</a>`, `<a>x</a>`},
+		{"data image", `<img src="data:image/svg+xml;base64,PHN2Zz4=" alt="d">`, ``},
+		{"mailto image", `<img src="mailto:a@example.com" alt="m">`, ``},
+		{"javascript poster", `<video src="https://example.com/v.mp4" poster="javascript:alert(1)" controls></video>`, `<video src="https://example.com/v.mp4" controls=""></video>`},
+		{"javascript cite", `<blockquote cite="javascript:alert(1)">q</blockquote>`, `<blockquote>q</blockquote>`},
+		{"https link", `<a href="https://example.com/a" title="t">ok</a>`, `<a href="https://example.com/a" title="t" rel="nofollow noopener noreferrer">ok</a>`},
+		{"relative link", `<a href="/relative">rel</a>`, `<a href="/relative" rel="nofollow noopener noreferrer">rel</a>`},
+		{"mailto link", `<a href="mailto:a@example.com">mail</a>`, `<a href="mailto:a@example.com" rel="nofollow noopener noreferrer">mail</a>`},
+		{"target and rel replaced", `<a href="https://example.com" target="_blank" rel="opener">x</a>`, `<a href="https://example.com" rel="nofollow noopener noreferrer">x</a>`},
+
+		//event handlers and styles
+		{"onerror", `<img src="x.png" onerror="alert(1)" alt="a">`, `<img src="x.png" alt="a">`},
+		{"onclick and style", `<p onclick="alert(1)" style="color:red">p</p>`, `<p>p</p>`},
+		{"onload on body", `<body onload="alert(1)">b</body>`, `b`},
+
+		//foreign content
+		{"script in svg", `<svg><script>alert(1)</script></svg>after`, `after`},
+		{"xlink in svg", `<svg><a xlink:href="javascript:alert(1)">x</a></svg>`, ``},
+		{"math mglyph style", `<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>`, ``},
+		{"svg p style", `<svg><p><style><img src=x onerror=alert(1)></style></p></svg>`, ``},
+		{"xlink in math", `<math><mi xlink:href="javascript:alert(1)">x</mi></math>`, ``},
+
+		//parser differentials
+		{"noscript attribute breakout", `<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>`, `<img src="x">&#34;&gt;`},
+		{"base", `<base href="https://evil.example.net/"><a href="x">x</a>`, `<a href="x" rel="nofollow noopener noreferrer">x</a>`},
+		{"meta refresh", `<meta http-equiv="refresh" content="0;url=javascript:alert(1)">`, ``},
+
+		//dropped and unwrapped elements
+		{"script", `<script>alert(1)</script><p>text</p>`, `<p>text</p>`},
+		{"style", `<style>body{}</style><p>text</p>`, `<p>text</p>`},
+		{"form", `<form action="https://evil"><input name=x></form>`, ``},
+		{"object and embed", `<object data="x.swf"></object><embed src="x.swf">`, ``},
+		{"unknown element keeps text", `<custom-el>kept text</custom-el>`, `kept text`},
+		{"comment", `<!-- comment --><p>x</p>`, `<p>x</p>`},
+		{"text is escaped", `<p>5 &lt; 6 &amp; "q"</p>`, `<p>5 &lt; 6 &amp; &#34;q&#34;</p>`},
+
+		//iframes
+		{"untrusted iframe", `<iframe src="https://evil.example.net/embed"></iframe>`, ``},
+		{"allowlisted iframe", `<iframe src="https://www.youtube.com/embed/abc" width="560" height="315" allowfullscreen></iframe>`, `<iframe src="https://www.youtube.com/embed/abc" width="560" height="315" allowfullscreen=""></iframe>`},
+		{"configured iframe host", `<iframe src="https://embed.example.com/x"></iframe>`, `<iframe src="https://embed.example.com/x"></iframe>`},
+		{"allowlisted host over http", `<iframe src="http://www.youtube.com/embed/abc"></iframe>`, ``},
+		{"allowlisted host as prefix", `<iframe src="https://www.youtube.com.evil.net/embed/abc"></iframe>`, ``},
+		{"srcdoc iframe", `<iframe srcdoc="<script>alert(1)</script>"></iframe>`, ``},
+
+		//tracking pixels
+		{"1x1 pixel", `<img src="https://example.com/p.gif" width="1" height="1">`, ``},
+		{"px and zero sizes", `<img src="https://example.com/p.gif" width="1px" height="0">`, ``},
+		{"hidden image", `<img src="https://example.com/p.gif" style="display: none">`, ``},
+		{"tracker host", `<img src="https://pixel.wp.com/g.gif?x=1">`, ``},
+		{"tracker subdomain", `<img src="https://a.doubleclick.net/p.gif">`, ``},
+		{"thin but wide image", `<img src="https://example.com/photo.jpg" width="600" height="1" alt="wide">`, `<img src="https://example.com/photo.jpg" width="600" height="1" alt="wide">`},
+	}
+
+	p := NewPolicy([]string{"embed.example.com"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.HTML(tt.src)
+			if got != tt.want {
+				t.Errorf("HTML(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPolicyDoesNotShareHosts(t *testing.T) {
+	NewPolicy([]string{"embed.example.com"})
+	p := NewPolicy(nil)
+	if got := p.HTML(`<iframe src="https://embed.example.com/x"></iframe>`); got != "" {
+		t.Errorf("iframe host leaked into another policy: %q", got)
+	}
+}
+
+// TestHostileFeed runs a feed full of attacks through the parser and the
+// sanitizer, the way posts are stored
+func TestHostileFeed(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "hostile-rss.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := feed.Parse("application/rss+xml", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(f.Items))
+	}
+
+	p := NewPolicy(nil)
+	forbidden := []string{
+		"<script", "javascript", "alert(", "onmouseover", "onerror", "style=",
+		"<svg", "<math", "<form", "<input", "<noscript", "evil.example.net",
+		"pixel.wp.com", "feedburner",
+	}
+	var stored []string
+	for _, item := range f.Items {
+		stored = append(stored, p.HTML(item.Description), p.HTML(item.Content))
+	}
+	all := strings.Join(stored, "\n")
+	for _, s := range forbidden {
+		if strings.Contains(strings.ToLower(all), s) {
+			t.Errorf("sanitized feed still contains %q:\n%s", s, all)
+		}
+	}
+
+	//the harmless parts survive
+	for _, s := range []string{
+		`Hover <a>here</a>`,
+		`<a>tab obfuscated link</a>`,
+		`<img src="https://blog.example.com/chart.png" alt="chart">`,
+		`<iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ" width="560" height="315"></iframe>`,
+		`<p>Overlay</p>`,
+		`<a href="https://blog.example.com/ok" rel="nofollow noopener noreferrer">fine</a>`,
+	} {
+		if !strings.Contains(all, s) {
+			t.Errorf("sanitized feed lost %q:\n%s", s, all)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package urlnorm canonicalizes feed and post URLs, so that the different ways
+// of writing one address (scheme and host case, default ports, tracking
+// parameters...) compare equal.
+package urlnorm
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+var ErrEmpty = errors.New("empty url")
+
+// trackingParams are query parameters that only identify where a click came
+// from, they never change the document a URL points to
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"yclid":   true,
+	"msclkid": true,
+}
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// IsTrackingParam reports whether the query parameter is a utm_* or other
+// click tracking parameter
+func IsTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "utm_") || trackingParams[name]
+}
+
+// Normalize cleans up an http(s) URL without changing the document it points
+// to: scheme and host are lowercased, the default port, the fragment and
+// tracking parameters are dropped, dot segments are resolved, the query is
+// sorted and an empty path becomes "/". URLs without a scheme are rejected,
+// use NormalizeInput for addresses typed by the user.
+func Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", ErrEmpty
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported url scheme: %s", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("url has no host: %s", raw)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		//ipv6 literal
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	//the escaped form is cleaned so an encoded slash stays part of its segment
+	escaped := cleanPath(u.EscapedPath())
+	if unescaped, err := url.PathUnescape(escaped); err == nil {
+		u.Path = unescaped
+		u.RawPath = escaped
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	u.RawQuery = cleanQuery(u.RawQuery)
+	u.ForceQuery = false
+	return u.String(), nil
+}
+
+// NormalizeInput is Normalize for an address typed by the user, which may
+// leave out the scheme: example.com/feed is taken to be https.
+func NormalizeInput(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw != "" && !strings.Contains(raw, "://") {
+		raw = "https://" + strings.TrimPrefix(raw, "//")
+	}
+	return Normalize(raw)
+}
+
+// Key is the form URLs are compared in: the normalized URL without its scheme
+// and trailing slash, so http and https or /feed and /feed/ are the same key.
+// It identifies an address but is not itself fetchable.
+func Key(raw string) (string, error) {
+	normalized, err := NormalizeInput(raw)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	key := u.Host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key, nil
+}
+
+// cleanQuery drops tracking parameters and sorts the rest by name, so
+// parameter order doesn't matter. Parameters are re-escaped the way
+// url.Values.Encode would, except that a bare "key" keeps its missing "=".
+// Queries that don't parse as key=value pairs (";" separators...) may mean
+// something to the server and are left alone.
+func cleanQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	if _, err := url.ParseQuery(rawQuery); err != nil {
+		return rawQuery
+	}
+
+	type param struct {
+		name    string
+		encoded string
+	}
+	params := []param{}
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, hasValue := strings.Cut(pair, "=")
+		name, _ = url.QueryUnescape(name)
+		value, _ = url.QueryUnescape(value)
+		if IsTrackingParam(name) {
+			continue
+		}
+		encoded := url.QueryEscape(name)
+		if hasValue {
+			encoded += "=" + url.QueryEscape(value)
+		}
+		params = append(params, param{name: url.QueryEscape(name), encoded: encoded})
+	}
+	//stable so repeated parameters keep their order, like Encode
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].name < params[j].name
+	})
+
+	encoded := make([]string, len(params))
+	for i, p := range params {
+		encoded[i] = p.encoded
+	}
+	return strings.Join(encoded, "&")
+}
+
+// cleanPath resolves . and .. segments, keeping a trailing slash since that
+// can matter to the server
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if !strings.Contains(p, "/.") {
+		return p
+	}
+	cleaned := path.Clean(p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
@@ -0,0 +1,150 @@
+package urlnorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"already normal", "https://example.com/feed.xml", "https://example.com/feed.xml"},
+		{"scheme and host case", "HTTPS://Example.COM/Feed", "https://example.com/Feed"},
+		{"surrounding space", "  https://example.com/feed  ", "https://example.com/feed"},
+		{"empty path", "https://example.com", "https://example.com/"},
+		{"trailing dot in host", "https://example.com./feed", "https://example.com/feed"},
+		{"default https port", "https://example.com:443/feed", "https://example.com/feed"},
+		{"default http port", "http://example.com:80/feed", "http://example.com/feed"},
+		{"other port", "https://example.com:8443/feed", "https://example.com:8443/feed"},
+		{"ipv6 host", "http://[::1]:80/feed", "http://[::1]/feed"},
+		{"fragment", "https://example.com/post#comments", "https://example.com/post"},
+		{"dot segments", "https://example.com/a/./b/../feed", "https://example.com/a/feed"},
+		{"dot segments keep trailing slash", "https://example.com/a/../b/", "https://example.com/b/"},
+		{"encoded slash", "https://example.com/a%2Fb/feed", "https://example.com/a%2Fb/feed"},
+		{"encoded slash with dot segments", "https://example.com/x/../a%2Fb/feed", "https://example.com/a%2Fb/feed"},
+		{"escaped space", "https://example.com/a%20b", "https://example.com/a%20b"},
+
+		//queries
+		{"query sorted", "https://example.com/feed?b=2&a=1", "https://example.com/feed?a=1&b=2"},
+		{"repeated parameter keeps order", "https://example.com/feed?t=2&a=1&t=1", "https://example.com/feed?a=1&t=2&t=1"},
+		{"tracking parameters", "https://example.com/post?id=5&utm_source=rss&UTM_Medium=feed&fbclid=x", "https://example.com/post?id=5"},
+		{"only tracking parameters", "https://example.com/post?utm_source=rss", "https://example.com/post"},
+		{"bare key", "https://example.com/?feed", "https://example.com/?feed"},
+		{"bare key with others", "https://example.com/?feed=rss2&atom", "https://example.com/?atom&feed=rss2"},
+		{"empty value", "https://example.com/?feed=", "https://example.com/?feed="},
+		{"semicolon separated", "https://example.com/feed?a=1;b=2", "https://example.com/feed?a=1;b=2"},
+		{"escaped value", "https://example.com/search?q=a%20b%26c", "https://example.com/search?q=a+b%26c"},
+		{"empty query", "https://example.com/feed?", "https://example.com/feed"},
+		{"empty pairs", "https://example.com/feed?a=1&&b=2", "https://example.com/feed?a=1&b=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.raw)
+			if err != nil {
+				t.Fatalf("Normalize(%q): %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+			//normalizing twice changes nothing
+			if again, _ := Normalize(got); again != got {
+				t.Errorf("Normalize(%q) = %q, not stable", got, again)
+			}
+		})
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"empty", ""},
+		{"relative link", "posts/1"},
+		{"absolute path", "/posts/1"},
+		{"no scheme", "example.com/feed"},
+		{"protocol relative", "//example.com/feed"},
+		{"ftp", "ftp://example.com/feed"},
+		{"mailto", "mailto:a@example.com"},
+		{"no host", "https:///feed"},
+		{"bad escape", "https://example.com/%zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Normalize(tt.raw); err == nil {
+				t.Errorf("Normalize(%q) = %q, want an error", tt.raw, got)
+			}
+		})
+	}
+
+	if _, err := Normalize("  "); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Normalize of blank = %v, want ErrEmpty", err)
+	}
+}
+
+func TestNormalizeInput(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"example.com/feed", "https://example.com/feed"},
+		{"Example.com", "https://example.com/"},
+		{"//example.com/feed", "https://example.com/feed"},
+		{"http://example.com/feed", "http://example.com/feed"},
+		{"example.com/?feed", "https://example.com/?feed"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeInput(tt.raw)
+		if err != nil {
+			t.Errorf("NormalizeInput(%q): %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeInput(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://example.com/feed/", "example.com/feed"},
+		{"http://Example.com/feed", "example.com/feed"},
+		{"example.com/feed?utm_source=x", "example.com/feed"},
+		{"https://example.com/", "example.com"},
+		{"https://example.com/feed?b=2&a=1", "example.com/feed?a=1&b=2"},
+		{"https://example.com/a%2Fb/", "example.com/a%2Fb"},
+	}
+
+	for _, tt := range tests {
+		got, err := Key(tt.raw)
+		if err != nil {
+			t.Errorf("Key(%q): %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestIsTrackingParam(t *testing.T) {
+	for _, name := range []string{"utm_source", "UTM_CAMPAIGN", "fbclid", "gclid", "mc_eid"} {
+		if !IsTrackingParam(name) {
+			t.Errorf("IsTrackingParam(%q) = false", name)
+		}
+	}
+	for _, name := range []string{"id", "page", "utm", "feed"} {
+		if IsTrackingParam(name) {
+			t.Errorf("IsTrackingParam(%q) = true", name)
+		}
+	}
+}
@@ -9,12 +9,15 @@ import (
 
 	"github.com/ercorn/gator/internal/config"
 	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/sanitize"
 	_ "github.com/lib/pq"
 )
 
 type state struct {
-	db  *database.Queries
-	cfg *config.Config
+	db        *database.Queries
+	cfg       *config.Config
+	fetcher   *fetcher
+	sanitizer *sanitize.Policy
 }
 
 func middlewareLoggedIn(handler func(s *state, cmd command, user database.User) error) func(*state, command) error {
@@ -41,9 +44,16 @@ func main() {
 	defer db.Close()
 	dbQueries := database.New(db)
 
+	feed_fetcher, err := newFetcher(cfg.Fetch)
+	if err != nil {
+		log.Fatalf("error in fetch config: %v", err)
+	}
+
 	progam_state := &state{
-		db:  dbQueries,
-		cfg: &cfg,
+		db:        dbQueries,
+		cfg:       &cfg,
+		fetcher:   feed_fetcher,
+		sanitizer: sanitize.NewPolicy(cfg.TrustedIframeHosts),
 	}
 
 	cmds := commands{
@@ -61,6 +71,13 @@ func main() {
 	cmds.register("following", middlewareLoggedIn(handlerFollowing))
 	cmds.register("unfollow", middlewareLoggedIn(handlerUnfollow))
 	cmds.register("browse", middlewareLoggedIn(handlerBrowse))
+	cmds.register("read", handlerRead)
+	cmds.register("download", handlerDownload)
+	cmds.register("setinterval", handlerSetInterval)
+	cmds.register("feedhealth", handlerFeedHealth)
+	cmds.register("enablefeed", handlerEnableFeed)
+	cmds.register("fetchlog", handlerFetchLog)
+	cmds.register("canonicalize", handlerCanonicalize)
 
 	//parse arguments and run the requested command
 	if len(os.Args) < 2 {
@@ -3,94 +3,154 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/xml"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"html"
-	"io"
+	"log"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/feed"
+	"github.com/ercorn/gator/internal/htmltext"
+	"github.com/ercorn/gator/internal/urlnorm"
 	"github.com/google/uuid"
 )
 
-type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+// cacheValidators are the response headers remembered from the previous fetch
+// of a feed, sent back so the server can answer 304 Not Modified
+type cacheValidators struct {
+	ETag         string
+	LastModified string
 }
 
-type RSSFeed struct {
-	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Item        []RSSItem `xml:"item"`
-	} `xml:"channel"`
+type fetchResult struct {
+	// Feed is nil when NotModified is set
+	Feed        *feed.Feed
+	NotModified bool
+	Validators  cacheValidators
+	// StatusCode is 0 when no response was received
+	StatusCode int
+	Bytes      int64
+	// MovedTo is the feed's new URL when every redirect followed was permanent
+	MovedTo string
 }
 
-func fetchFeed(ctx context.Context, feedUrl string) (*RSSFeed, error) {
+// fetchFeed downloads and parses the feed. The result is returned even along
+// with an error, filled in as far as the fetch got, so it can be recorded.
+func (f *fetcher) fetchFeed(ctx context.Context, feedUrl string, validators cacheValidators) (*fetchResult, error) {
+	result := &fetchResult{}
 	req, err := http.NewRequestWithContext(ctx, "GET", feedUrl, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return result, fmt.Errorf("error creating request: %w", err)
 	}
 	req.Header.Set("User-Agent", "gator")
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
 
-	client := http.Client{}
-	res, err := client.Do(req)
+	res, err := f.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+		return result, fmt.Errorf("error sending request: %w", err)
 	}
 	defer res.Body.Close()
+	result.StatusCode = res.StatusCode
+	result.MovedTo = permanentRedirect(res)
+
+	if res.StatusCode == http.StatusNotModified {
+		//a 304 may omit the validators, keep the ones we sent in that case
+		result.NotModified = true
+		result.Validators = validators
+		if etag := res.Header.Get("ETag"); etag != "" {
+			result.Validators.ETag = etag
+		}
+		if last_modified := res.Header.Get("Last-Modified"); last_modified != "" {
+			result.Validators.LastModified = last_modified
+		}
+		return result, nil
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return result, fmt.Errorf("unexpected response status: %s", res.Status)
+	}
 
-	body, err := io.ReadAll(res.Body)
+	body, err := f.readBody(res)
+	result.Bytes = int64(len(body))
 	if err != nil {
-		return nil, fmt.Errorf("error reading request: %w", err)
+		return result, fmt.Errorf("error reading request: %w", err)
 	}
 
-	feed := RSSFeed{}
-	err = xml.Unmarshal(body, &feed)
+	result.Feed, err = feed.Parse(res.Header.Get("Content-Type"), body)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling xml: %w", err)
+		return result, err
 	}
 
-	//decode escaped HTML entities (like &ldquo;) with html.UnescapeString(...)
-	//specifically decode Title and Description fields of the channel and the
-	//individual items
-	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
-	feed.Channel.Description = html.UnescapeString(feed.Channel.Description)
-	for i := range feed.Channel.Item {
-		feed.Channel.Item[i].Title = html.UnescapeString(feed.Channel.Item[i].Title)
-		feed.Channel.Item[i].Description = html.UnescapeString(feed.Channel.Item[i].Description)
+	result.Validators = cacheValidators{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
 	}
-
-	return &feed, nil
+	return result, nil
 }
 
-func handlerAgg(s *state, cmd command) error {
-	if len(cmd.args) != 1 {
-		return fmt.Errorf("usage: %s <time_between_reqs>", cmd.name)
+// permanentRedirect returns the final URL of a response that was reached only
+// through 301/308 redirects, or "" if there was none or any hop was temporary
+func permanentRedirect(res *http.Response) string {
+	if res.Request == nil || res.Request.Response == nil {
+		return ""
+	}
+	req := res.Request
+	for req.Response != nil {
+		status := req.Response.StatusCode
+		if status != http.StatusMovedPermanently && status != http.StatusPermanentRedirect {
+			return ""
+		}
+		req = req.Response.Request
 	}
+	if req.URL.String() == res.Request.URL.String() {
+		return ""
+	}
+	return res.Request.URL.String()
+}
 
-	//parse time_between_reqs
-	time_between_reqs, err := time.ParseDuration(cmd.args[0])
+// validateFeed makes a test fetch so that only URLs serving a feed we can
+// parse get added. The URL is returned updated if the feed has moved.
+func validateFeed(ctx context.Context, s *state, feedUrl string) (*feed.Feed, string, error) {
+	result, err := s.fetcher.fetchFeed(ctx, feedUrl, cacheValidators{})
 	if err != nil {
-		return fmt.Errorf("failed to parse time: %w", err)
+		return nil, "", fmt.Errorf("%s is not a usable feed: %w", feedUrl, err)
+	}
+	if result.MovedTo != "" {
+		feedUrl = result.MovedTo
 	}
+	return result.Feed, feedUrl, nil
+}
+
+// feedMetadata is the channel information stored with the feed
+func feedMetadata(feedID uuid.UUID, f *feed.Feed) database.UpdateFeedMetadataParams {
+	return database.UpdateFeedMetadataParams{
+		ID:          feedID,
+		Title:       nullString(f.Title),
+		Description: nullString(f.Description),
+		SiteUrl:     nullString(f.Link),
+		Language:    nullString(f.Language),
+		ImageUrl:    nullString(f.Image),
+	}
+}
 
-	fmt.Println("Collecting feeds every", time_between_reqs.String())
-	ticker := time.NewTicker(time_between_reqs)
-	for ; ; <-ticker.C {
-		fmt.Println("============================================================")
-		scrapeFeeds(s)
+func nullString(s string) sql.NullString {
+	return sql.NullString{
+		String: s,
+		Valid:  s != "",
 	}
 }
 
 func handlerAddFeed(s *state, cmd command, user database.User) error {
-	if len(cmd.args) != 2 {
-		return fmt.Errorf("usage: %s <feed_name> <url>", cmd.name)
+	if len(cmd.args) < 1 || len(cmd.args) > 2 {
+		return fmt.Errorf("usage: %s [feed_name] <url>", cmd.name)
 	}
 
 	ctx := context.Background()
@@ -99,13 +159,59 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 	// 	return fmt.Errorf("failed to get user: %w", err)
 	// }
 
+	raw_url, err := urlnorm.NormalizeInput(cmd.args[len(cmd.args)-1])
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	//users often paste the site's address instead of its feed
+	feed_url, err := discoverFeed(ctx, s, raw_url, os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	fetched_feed, feed_url, err := validateFeed(ctx, s, feed_url)
+	if err != nil {
+		return err
+	}
+	feed_url, canonical_url, err := canonicalFeedUrl(feed_url)
+	if err != nil {
+		return err
+	}
+
+	//the same feed under another spelling of its url, or one of its old urls,
+	//just follow that one
+	existing, err := lookupFeed(ctx, s, feed_url)
+	if err == nil {
+		fmt.Printf("Feed already added as %s (%s)\n", htmltext.StripControl(existing.Name), htmltext.StripControl(existing.Url))
+		return handlerFollow(s, command{name: "follow", args: []string{existing.Url}}, user)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to look up feed: %w", err)
+	}
+
+	//the title comes from the feed, keep terminal escapes out of the name
+	name := strings.TrimSpace(htmltext.StripControl(fetched_feed.Title))
+	if len(cmd.args) == 2 {
+		name = cmd.args[0]
+	}
+	if name == "" {
+		return fmt.Errorf("feed at %s has no title, give it a name: %s <feed_name> <url>", feed_url, cmd.name)
+	}
+
+	meta := feedMetadata(uuid.New(), fetched_feed)
 	feed, err := s.db.CreateFeed(ctx, database.CreateFeedParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		Name:      cmd.args[0],
-		Url:       cmd.args[1],
-		UserID:    user.ID,
+		ID:           meta.ID,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+		Name:         name,
+		Url:          feed_url,
+		UserID:       user.ID,
+		Title:        meta.Title,
+		Description:  meta.Description,
+		SiteUrl:      meta.SiteUrl,
+		Language:     meta.Language,
+		ImageUrl:     meta.ImageUrl,
+		CanonicalUrl: canonical_url,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create feed: %w", err)
@@ -118,7 +224,7 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 	//Automatically create a feed follow record for the current user when they add a feed.
 	f_f_cmd := command{
 		name: "follow",
-		args: []string{cmd.args[1]},
+		args: []string{feed_url},
 	}
 	err = handlerFollow(s, f_f_cmd, user)
 	if err != nil {
@@ -129,6 +235,34 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 
 }
 
+// canonicalFeedUrl returns the normalized url of a feed, which is the one
+// stored, and the key that different spellings of it share
+func canonicalFeedUrl(feedUrl string) (string, sql.NullString, error) {
+	normalized, err := urlnorm.Normalize(feedUrl)
+	if err != nil {
+		return "", sql.NullString{}, fmt.Errorf("invalid feed url %s: %w", feedUrl, err)
+	}
+	key, err := urlnorm.Key(normalized)
+	if err != nil {
+		return "", sql.NullString{}, fmt.Errorf("invalid feed url %s: %w", feedUrl, err)
+	}
+	return normalized, nullString(key), nil
+}
+
+// lookupFeed finds a feed by its url, one of its old urls, or any other
+// spelling of its url (http/https, trailing slash, tracking parameters...)
+func lookupFeed(ctx context.Context, s *state, feedUrl string) (database.Feed, error) {
+	feed, err := s.db.GetFeedByUrl(ctx, feedUrl)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return feed, err
+	}
+	key, key_err := urlnorm.Key(feedUrl)
+	if key_err != nil {
+		return feed, err
+	}
+	return s.db.GetFeedByCanonicalUrl(ctx, nullString(key))
+}
+
 func handlerFeeds(s *state, cmd command) error {
 	if len(cmd.args) != 0 {
 		return fmt.Errorf("usage: %s", cmd.name)
@@ -163,62 +297,450 @@ func printFeed(feed database.Feed, user database.User) {
 	fmt.Printf("* ID:            %s\n", feed.ID)
 	fmt.Printf("* Created:       %v\n", feed.CreatedAt)
 	fmt.Printf("* Updated:       %v\n", feed.UpdatedAt)
-	fmt.Printf("* Name:          %s\n", feed.Name)
-	fmt.Printf("* URL:           %s\n", feed.Url)
+	fmt.Printf("* Name:          %s\n", htmltext.StripControl(feed.Name))
+	fmt.Printf("* URL:           %s\n", htmltext.StripControl(feed.Url))
+	if feed.Title.Valid {
+		fmt.Printf("* Title:         %s\n", htmltext.StripControl(feed.Title.String))
+	}
+	if feed.SiteUrl.Valid {
+		fmt.Printf("* Site:          %s\n", htmltext.StripControl(feed.SiteUrl.String))
+	}
+	if feed.Language.Valid {
+		fmt.Printf("* Language:      %s\n", htmltext.StripControl(feed.Language.String))
+	}
 	fmt.Printf("* User:          %s\n", user.Name)
+	fmt.Printf("* Interval:      %s\n", describeInterval(feed))
+}
+
+// scrapeOutcome describes a fetch attempt for the fetch log and scheduling
+type scrapeOutcome struct {
+	// Hints are the feed's scheduling hints, the ones saved by an earlier
+	// fetch when there was nothing new to parse
+	Hints        feed.ScheduleHints
+	StatusCode   int
+	Bytes        int64
+	Items        int
+	NewPosts     int
+	UpdatedPosts int
 }
 
-func scrapeFeeds(s *state) {
-	next_feed, err := s.db.GetNextFeedToFetch(context.Background())
+// scrapeFeed fetches a feed that has already been claimed by the caller and
+// stores its items as posts. Cancelling ctx aborts the download, but a
+// downloaded feed is always stored completely.
+func scrapeFeed(ctx context.Context, s *state, logger *log.Logger, next_feed database.Feed) (scrapeOutcome, error) {
+	outcome := scrapeOutcome{Hints: storedScheduleHints(next_feed)}
+	result, err := s.fetcher.fetchFeed(ctx, next_feed.Url, cacheValidators{
+		ETag:         next_feed.Etag.String,
+		LastModified: next_feed.LastModified.String,
+	})
+	outcome.StatusCode = result.StatusCode
+	outcome.Bytes = result.Bytes
 	if err != nil {
-		fmt.Println("failed to get next feed:", err)
-		return
+		return outcome, fmt.Errorf("failed to fetch feed %s: %w", next_feed.Name, err)
+	}
+
+	write_ctx := context.WithoutCancel(ctx)
+	if result.MovedTo != "" {
+		err = moveFeed(write_ctx, s, next_feed, result.MovedTo)
+		if err != nil {
+			logger.Println("failed to update feed url:", err)
+		} else {
+			logger.Printf("Feed %s moved permanently to %s\n", htmltext.StripControl(next_feed.Name), htmltext.StripControl(result.MovedTo))
+		}
+	}
+
+	if result.NotModified {
+		logger.Printf("Feed %s not modified since last fetch\n", htmltext.StripControl(next_feed.Name))
+		return outcome, saveCacheValidators(write_ctx, s, next_feed.ID, result.Validators)
 	}
 
-	_, err = s.db.MarkFeedFetched(context.Background(), next_feed.ID)
+	fetched_feed := result.Feed
+	outcome.Hints = fetched_feed.Schedule
+	outcome.Items = len(fetched_feed.Items)
+	hints, err := json.Marshal(fetched_feed.Schedule)
+	if err == nil {
+		err = s.db.UpdateFeedScheduleHints(write_ctx, database.UpdateFeedScheduleHintsParams{
+			ID:            next_feed.ID,
+			ScheduleHints: hints,
+		})
+	}
 	if err != nil {
-		fmt.Println("failed to mark feed as fetched:", err)
-		return
+		logger.Println("failed to save schedule hints:", err)
+	}
+	err = s.db.UpdateFeedMetadata(write_ctx, feedMetadata(next_feed.ID, fetched_feed))
+	if err != nil {
+		logger.Println("failed to save feed metadata:", err)
 	}
 
-	fetched_feed, err := fetchFeed(context.Background(), next_feed.Url)
+	err = rekeyLegacyPosts(write_ctx, s, next_feed.ID, fetched_feed.Items)
 	if err != nil {
-		fmt.Println("failed to fetch feed:", err)
-		return
+		logger.Println(err)
 	}
 
-	fmt.Println("Fetched feed:", fetched_feed.Channel.Title)
-	for _, item := range fetched_feed.Channel.Item {
+	fetched_at := time.Now().UTC()
+	logger.Println("Fetched feed:", htmltext.StripControl(fetched_feed.Title))
+	for _, item := range fetched_feed.Items {
 
+		//published_at has no time zone, the offset would be dropped and the
+		//publisher's local time stored
 		published_at := sql.NullTime{}
-		if t, err := time.Parse(time.RFC1123Z, item.PubDate); err == nil {
+		if !item.Published.IsZero() {
+			published_at = sql.NullTime{
+				Time:  item.Published.UTC(),
+				Valid: true,
+			}
+		} else if s.cfg.DateFallbackToFetchTime {
 			published_at = sql.NullTime{
-				Time:  t,
+				Time:  fetched_at,
 				Valid: true,
 			}
 		}
-		_, err := s.db.CreatePost(context.Background(), database.CreatePostParams{
+		stored, err := storePost(write_ctx, s, next_feed.ID, item, published_at)
+		if err != nil {
+			logger.Println("Couldn't store post:", err)
+			continue
+		}
+		switch stored {
+		case postCreated:
+			outcome.NewPosts++
+		case postUpdated:
+			outcome.UpdatedPosts++
+		}
+	}
+
+	//only remember the validators once the posts are stored, otherwise an
+	//interrupted run would get a 304 next time and never see these items
+	err = saveCacheValidators(write_ctx, s, next_feed.ID, result.Validators)
+	if err != nil {
+		return outcome, err
+	}
+
+	logger.Printf("Feed %s collected, %v posts found, %v new, %v updated\n", htmltext.StripControl(next_feed.Name), outcome.Items, outcome.NewPosts, outcome.UpdatedPosts)
+	return outcome, nil
+}
+
+type postResult int
+
+const (
+	postUnchanged postResult = iota
+	postCreated
+	postUpdated
+)
+
+// storePost saves the item as a post along with its enclosures, authors and
+// categories
+func storePost(ctx context.Context, s *state, feedID uuid.UUID, item feed.Item, publishedAt sql.NullTime) (postResult, error) {
+	result, post_id, err := upsertPost(ctx, s, feedID, item, publishedAt)
+	if err != nil {
+		return postUnchanged, err
+	}
+
+	//an edited item may have been retagged or had its media replaced, start
+	//its lists over
+	if result == postUpdated {
+		err = s.db.DeletePostEnclosures(ctx, post_id)
+		if err != nil {
+			return postUnchanged, fmt.Errorf("failed to clear post enclosures: %w", err)
+		}
+		err = s.db.DeletePostAuthors(ctx, post_id)
+		if err != nil {
+			return postUnchanged, fmt.Errorf("failed to clear post authors: %w", err)
+		}
+		err = s.db.DeletePostCategories(ctx, post_id)
+		if err != nil {
+			return postUnchanged, fmt.Errorf("failed to clear post categories: %w", err)
+		}
+	}
+	for _, author := range item.Authors {
+		err = s.db.CreatePostAuthor(ctx, database.CreatePostAuthorParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			PostID:    post_id,
+			Name:      author,
+		})
+		if err != nil {
+			return postUnchanged, fmt.Errorf("failed to save post author: %w", err)
+		}
+	}
+	for _, category := range item.Categories {
+		err = s.db.CreatePostCategory(ctx, database.CreatePostCategoryParams{
 			ID:        uuid.New(),
 			CreatedAt: time.Now().UTC(),
-			UpdatedAt: time.Now().UTC(),
-			Title:     item.Title,
-			Url:       item.Link,
-			Description: sql.NullString{
-				String: item.Description,
-				Valid:  true,
-			},
-			PublishedAt: published_at,
-			FeedID:      next_feed.ID,
+			PostID:    post_id,
+			Name:      category,
 		})
+		if err != nil {
+			return postUnchanged, fmt.Errorf("failed to save post category: %w", err)
+		}
+	}
 
+	for _, enclosure := range item.Enclosures {
+		err = s.db.CreatePostEnclosure(ctx, database.CreatePostEnclosureParams{
+			ID:              uuid.New(),
+			CreatedAt:       time.Now().UTC(),
+			PostID:          post_id,
+			Url:             enclosure.URL,
+			MimeType:        enclosure.Type,
+			Length:          sql.NullInt64{Int64: enclosure.Length, Valid: enclosure.Length > 0},
+			DurationSeconds: sql.NullInt32{Int32: int32(enclosure.Duration.Seconds()), Valid: enclosure.Duration > 0},
+		})
 		if err != nil {
-			if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-				continue //ignore duplicates
-			}
-			fmt.Println("Couldn't create post: %w", err)
+			return postUnchanged, fmt.Errorf("failed to save enclosure: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// postUrl is the url a post is stored with, the normalized link when it can be
+// normalized
+func postUrl(item feed.Item) string {
+	link, err := urlnorm.Normalize(item.Link)
+	if err != nil {
+		return strings.TrimSpace(item.Link)
+	}
+	return link
+}
+
+// rekeyLegacyPosts deals with the posts migration 007 keyed by their raw url:
+// an item keyed by its guid (or by a link that normalizes differently) would
+// be inserted a second time, so the old row takes the item's key instead.
+// One indexed query finds the few items that have such a post.
+func rekeyLegacyPosts(ctx context.Context, s *state, feedID uuid.UUID, items []feed.Item) error {
+	urls := []string{}
+	for _, item := range items {
+		if link := postUrl(item); link != "" {
+			urls = append(urls, strings.TrimSpace(item.Link), link)
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	url_keyed, err := s.db.GetUrlKeyedPosts(ctx, database.GetUrlKeyedPostsParams{
+		FeedID: feedID,
+		Urls:   urls,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get url keyed posts: %w", err)
+	}
+	if len(url_keyed) == 0 {
+		return nil
+	}
+
+	legacy := map[string]bool{}
+	for _, u := range url_keyed {
+		legacy[u] = true
+	}
+	for _, item := range items {
+		key, raw, link := item.Key(), strings.TrimSpace(item.Link), postUrl(item)
+		if !(legacy[raw] && raw != key) && !(legacy[link] && link != key) {
 			continue
 		}
+		_, err = s.db.RekeyLegacyPost(ctx, database.RekeyLegacyPostParams{
+			Guid:   key,
+			FeedID: feedID,
+			Urls:   []string{raw, link},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rekey legacy post: %w", err)
+		}
+	}
+	return nil
+}
+
+// upsertPost inserts the item as a new post, or, when the feed already has a
+// post with the same key but different content, overwrites that post with the
+// edited version (keeping the old one as a revision if configured). It returns
+// the id of the stored post.
+func upsertPost(ctx context.Context, s *state, feedID uuid.UUID, item feed.Item, publishedAt sql.NullTime) (postResult, uuid.UUID, error) {
+	key := item.Key()
+	content_hash := item.ContentHash()
+	link := postUrl(item)
+	//feed html is untrusted, only the sanitized version is shown
+	description := sql.NullString{
+		String: s.sanitizer.HTML(item.Description),
+		Valid:  true,
+	}
+	content := nullString(s.sanitizer.HTML(item.Content))
+	raw_description, raw_content := sql.NullString{}, sql.NullString{}
+	if s.cfg.KeepRawContent {
+		raw_description = nullString(item.Description)
+		raw_content = nullString(item.Content)
+	}
+
+	post_id := uuid.New()
+	created, err := s.db.CreatePost(ctx, database.CreatePostParams{
+		ID:             post_id,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
+		Title:          item.Title,
+		Url:            link,
+		Description:    description,
+		PublishedAt:    publishedAt,
+		FeedID:         feedID,
+		Guid:           key,
+		ContentHash:    content_hash,
+		Content:        content,
+		RawDescription: raw_description,
+		RawContent:     raw_content,
+	})
+	if err != nil {
+		return postUnchanged, uuid.Nil, fmt.Errorf("failed to create post: %w", err)
+	}
+	if created > 0 {
+		return postCreated, post_id, nil
+	}
+
+	existing, err := s.db.GetPostByFeedAndGuid(ctx, database.GetPostByFeedAndGuidParams{
+		FeedID: feedID,
+		Guid:   key,
+	})
+	if err != nil {
+		return postUnchanged, uuid.Nil, fmt.Errorf("failed to get existing post: %w", err)
+	}
+	if existing.ContentHash == content_hash {
+		return postUnchanged, existing.ID, nil
+	}
+
+	//posts stored before content hashes existed have an empty hash, and ones
+	//stored before full content was captured have none, those just get it
+	//filled in rather than showing up as edited
+	backfill := existing.ContentHash == ""
+	if !existing.Content.Valid && content.Valid {
+		without_content := item
+		without_content.Content = ""
+		backfill = backfill || without_content.ContentHash() == existing.ContentHash
+	}
+
+	result := postUpdated
+	updated_at := time.Now().UTC()
+	if backfill {
+		result = postUnchanged
+		updated_at = existing.UpdatedAt
+	} else if s.cfg.KeepPostRevisions {
+		err = s.db.CreatePostRevision(ctx, database.CreatePostRevisionParams{
+			ID:          uuid.New(),
+			CreatedAt:   time.Now().UTC(),
+			PostID:      existing.ID,
+			Title:       existing.Title,
+			Url:         existing.Url,
+			Description: existing.Description,
+			PublishedAt: existing.PublishedAt,
+			ContentHash: existing.ContentHash,
+			Content:     existing.Content,
+		})
+		if err != nil {
+			return postUnchanged, uuid.Nil, fmt.Errorf("failed to save post revision: %w", err)
+		}
 	}
 
-	fmt.Printf("Feed %s collected, %v posts found\n", next_feed.Name, len(fetched_feed.Channel.Item))
+	//a fetch time fallback date would move the post on every edit
+	if item.Published.IsZero() {
+		publishedAt = existing.PublishedAt
+	}
+
+	err = s.db.UpdatePost(ctx, database.UpdatePostParams{
+		ID:             existing.ID,
+		Title:          item.Title,
+		Url:            link,
+		Description:    description,
+		PublishedAt:    publishedAt,
+		ContentHash:    content_hash,
+		UpdatedAt:      updated_at,
+		Content:        content,
+		RawDescription: raw_description,
+		RawContent:     raw_content,
+	})
+	if err != nil {
+		return postUnchanged, uuid.Nil, fmt.Errorf("failed to update post: %w", err)
+	}
+	return result, existing.ID, nil
+}
+
+// sanitizeBatchSize is how many unsanitized posts are loaded at a time
+const sanitizeBatchSize = 500
+
+// sanitizeStoredPosts runs the html of posts stored before sanitizing at
+// ingest existed through the sanitizer, those would otherwise keep their
+// scripts until the publisher edits them. It returns how many posts it fixed.
+func sanitizeStoredPosts(ctx context.Context, s *state) (int, error) {
+	sanitized := 0
+	for {
+		posts, err := s.db.GetUnsanitizedPosts(ctx, sanitizeBatchSize)
+		if err != nil {
+			return sanitized, fmt.Errorf("failed to get unsanitized posts: %w", err)
+		}
+		if len(posts) == 0 {
+			return sanitized, nil
+		}
+
+		for _, post := range posts {
+			//the original html is kept first, it can't be recovered later
+			raw_description, raw_content := sql.NullString{}, sql.NullString{}
+			if s.cfg.KeepRawContent {
+				raw_description = post.Description
+				raw_content = post.Content
+			}
+			description := post.Description
+			if description.Valid {
+				description.String = s.sanitizer.HTML(description.String)
+			}
+			content := post.Content
+			if content.Valid {
+				content = nullString(s.sanitizer.HTML(content.String))
+			}
+			err = s.db.SetPostSanitized(ctx, database.SetPostSanitizedParams{
+				Description:    description,
+				Content:        content,
+				RawDescription: raw_description,
+				RawContent:     raw_content,
+				ID:             post.ID,
+			})
+			if err != nil {
+				return sanitized, fmt.Errorf("failed to save sanitized post: %w", err)
+			}
+			sanitized++
+		}
+	}
+}
+
+// moveFeed points the feed at its new URL, keeping the old one as an alias so
+// it can still be used to look the feed up
+func moveFeed(ctx context.Context, s *state, f database.Feed, newUrl string) error {
+	newUrl, canonical_url, err := canonicalFeedUrl(newUrl)
+	if err != nil {
+		return err
+	}
+	if newUrl == f.Url {
+		return nil
+	}
+
+	err = s.db.CreateFeedUrlAlias(ctx, database.CreateFeedUrlAliasParams{
+		Url:       f.Url,
+		CreatedAt: time.Now().UTC(),
+		FeedID:    f.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save old feed url: %w", err)
+	}
+	_, err = s.db.UpdateFeedUrl(ctx, database.UpdateFeedUrlParams{
+		ID:           f.ID,
+		Url:          newUrl,
+		CanonicalUrl: canonical_url,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update feed url: %w", err)
+	}
+	return nil
+}
+
+func saveCacheValidators(ctx context.Context, s *state, feedID uuid.UUID, validators cacheValidators) error {
+	err := s.db.UpdateFeedCacheHeaders(ctx, database.UpdateFeedCacheHeadersParams{
+		ID:           feedID,
+		Etag:         nullString(validators.ETag),
+		LastModified: nullString(validators.LastModified),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save feed cache headers: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/feed"
+	"github.com/google/uuid"
+)
+
+const (
+	// adaptiveSampleSize is how many recent posts the adaptive mode looks at
+	adaptiveSampleSize  = 20
+	minAdaptiveInterval = 15 * time.Minute
+	maxAdaptiveInterval = 24 * time.Hour
+
+	// failing feeds wait between minBackoff and maxBackoff before retrying
+	minBackoff = time.Minute
+	maxBackoff = 24 * time.Hour
+)
+
+// fetchInterval decides how long to wait between fetches of the feed: its
+// own interval if one is set, learned from its posts in adaptive mode, the
+// agg default otherwise. The publisher's ttl and sy:updatePeriod are a floor,
+// capped at maxAdaptiveInterval so a ttl of a year can't stop a feed's updates.
+func fetchInterval(ctx context.Context, s *state, f database.Feed, hints feed.ScheduleHints, defaultInterval time.Duration) time.Duration {
+	interval := defaultInterval
+	switch {
+	case f.Adaptive:
+		interval = adaptiveInterval(ctx, s, f.ID, defaultInterval)
+	case f.FetchIntervalSeconds.Valid:
+		interval = time.Duration(f.FetchIntervalSeconds.Int32) * time.Second
+	}
+	return max(interval, min(hints.MinInterval(), maxAdaptiveInterval))
+}
+
+// adaptiveInterval polls twice per typical (median) gap between the feed's
+// recent posts, a feed without enough dated posts gets the default
+func adaptiveInterval(ctx context.Context, s *state, feedID uuid.UUID, defaultInterval time.Duration) time.Duration {
+	dates, err := s.db.GetRecentPostDates(ctx, database.GetRecentPostDatesParams{
+		FeedID: feedID,
+		Limit:  adaptiveSampleSize,
+	})
+	if err != nil || len(dates) < 2 {
+		return defaultInterval
+	}
+
+	//dates come newest first
+	gaps := []time.Duration{}
+	for i := 1; i < len(dates); i++ {
+		gaps = append(gaps, dates[i-1].Time.Sub(dates[i].Time))
+	}
+	slices.Sort(gaps)
+	median := gaps[len(gaps)/2]
+
+	return min(max(median/2, minAdaptiveInterval), maxAdaptiveInterval)
+}
+
+// backoffInterval doubles the interval for every consecutive failure
+func backoffInterval(interval time.Duration, failures int32) time.Duration {
+	backoff := max(interval, minBackoff)
+	for i := int32(0); i < failures && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// nextFetchDelay is the time until the next fetch, pushed past the hours and
+// days the publisher asked to be skipped
+func nextFetchDelay(now time.Time, interval time.Duration, hints feed.ScheduleHints) time.Duration {
+	next := now.Add(interval)
+	//a week of hours covers any combination of skipHours and skipDays
+	for i := 0; i < 24*7 && hints.Skipped(next); i++ {
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+	return next.Sub(now)
+}
+
+// storedScheduleHints returns the hints saved from the last full fetch, used
+// when the feed couldn't be parsed this time (304, errors)
+func storedScheduleHints(f database.Feed) feed.ScheduleHints {
+	hints := feed.ScheduleHints{}
+	_ = json.Unmarshal(f.ScheduleHints, &hints)
+	return hints
+}
+
+// scheduleNextFetch sets next_fetch_at, backing off while the feed is failing
+func scheduleNextFetch(ctx context.Context, s *state, f database.Feed, hints feed.ScheduleHints, defaultInterval time.Duration) (time.Duration, error) {
+	interval := fetchInterval(ctx, s, f, hints, defaultInterval)
+	if f.ConsecutiveFailures > 0 {
+		interval = backoffInterval(interval, f.ConsecutiveFailures)
+	}
+	delay := nextFetchDelay(time.Now(), interval, hints)
+	err := s.db.ScheduleNextFetch(ctx, database.ScheduleNextFetchParams{
+		DelaySeconds: delay.Seconds(),
+		ID:           f.ID,
+	})
+	return delay, err
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ercorn/gator/internal/database"
+	"github.com/ercorn/gator/internal/feed"
+)
+
+func TestFetchIntervalHints(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		hints    feed.ScheduleHints
+		want     time.Duration
+	}{
+		{"no hints", 0, feed.ScheduleHints{}, time.Minute},
+		{"ttl above the default", 0, feed.ScheduleHints{TTL: time.Hour}, time.Hour},
+		{"ttl below the default", 0, feed.ScheduleHints{TTL: 30 * time.Second}, time.Minute},
+		{"yearly ttl is capped", 0, feed.ScheduleHints{TTL: 525600 * time.Minute}, maxAdaptiveInterval},
+		{"yearly update period is capped", 0, feed.ScheduleHints{UpdatePeriod: 365 * 24 * time.Hour}, maxAdaptiveInterval},
+		{"own interval above the cap", 48 * time.Hour, feed.ScheduleHints{TTL: 525600 * time.Minute}, 48 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := database.Feed{}
+			if tt.interval > 0 {
+				f.FetchIntervalSeconds = sql.NullInt32{Int32: int32(tt.interval.Seconds()), Valid: true}
+			}
+			//without adaptive mode the database is never used
+			got := fetchInterval(context.Background(), nil, f, tt.hints, time.Minute)
+			if got != tt.want {
+				t.Errorf("fetchInterval = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+-- name: CreateFeedFetch :exec
+INSERT INTO feed_fetches (id, feed_id, started_at, duration_ms, status_code, bytes, item_count, new_item_count, updated_item_count, error)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+);
+
+-- name: GetFeedFetches :many
+SELECT feed_fetches.*, feeds.name AS feed_name, feeds.url AS feed_url FROM feed_fetches
+INNER JOIN feeds ON feeds.id = feed_fetches.feed_id
+WHERE sqlc.narg(feed_id)::uuid IS NULL OR feed_fetches.feed_id = sqlc.narg(feed_id)::uuid
+ORDER BY feed_fetches.started_at DESC
+LIMIT sqlc.arg(row_limit);
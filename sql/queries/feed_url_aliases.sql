@@ -0,0 +1,12 @@
+-- name: CreateFeedUrlAlias :exec
+INSERT INTO feed_url_aliases (url, created_at, feed_id)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT (url) DO UPDATE SET feed_id = EXCLUDED.feed_id, created_at = EXCLUDED.created_at;
+
+-- name: MoveFeedUrlAliases :exec
+UPDATE feed_url_aliases SET feed_id = sqlc.arg(to_feed_id)
+WHERE feed_id = sqlc.arg(from_feed_id);
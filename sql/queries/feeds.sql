@@ -1,12 +1,18 @@
 -- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, title, description, site_url, language, image_url, canonical_url)
 VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12
 )
 RETURNING *;
 
@@ -14,10 +20,88 @@ RETURNING *;
 SELECT * FROM feeds;
 
 -- name: GetFeedByUrl :one
-SELECT * FROM feeds WHERE url = $1;
+SELECT * FROM feeds
+WHERE url = $1
+    OR id IN (SELECT feed_id FROM feed_url_aliases WHERE feed_url_aliases.url = $1)
+LIMIT 1;
 
--- name: MarkFeedFetched :one
-UPDATE feeds SET last_fetched_at = NOW(), updated_at = NOW() WHERE id = $1 RETURNING *;
+-- name: GetFeedByCanonicalUrl :one
+SELECT * FROM feeds WHERE canonical_url = $1;
 
--- name: GetNextFeedToFetch :one
-SELECT * FROM feeds ORDER BY last_fetched_at ASC NULLS FIRST LIMIT 1;
+-- name: ClaimNextFeed :one
+UPDATE feeds
+SET last_fetched_at = NOW(),
+    updated_at = NOW(),
+    lease_expires_at = NOW() + make_interval(secs => sqlc.arg(lease_seconds)::float8),
+    leased_by = sqlc.arg(leased_by)::text
+WHERE id = (
+    SELECT id FROM feeds
+    WHERE feeds.disabled_at IS NULL
+        AND (feeds.lease_expires_at IS NULL OR feeds.lease_expires_at < NOW())
+        AND (feeds.next_fetch_at IS NULL OR feeds.next_fetch_at <= NOW())
+        AND NOT (feeds.id = ANY(sqlc.arg(claimed)::uuid[]))
+    ORDER BY feeds.next_fetch_at ASC NULLS FIRST
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ReleaseFeedLease :exec
+UPDATE feeds SET lease_expires_at = NULL, leased_by = NULL WHERE id = $1 AND leased_by = sqlc.arg(leased_by)::text;
+
+-- name: UpdateFeedCacheHeaders :exec
+UPDATE feeds SET etag = $2, last_modified = $3, updated_at = NOW() WHERE id = $1;
+
+-- name: ScheduleNextFetch :exec
+UPDATE feeds
+SET next_fetch_at = NOW() + make_interval(secs => sqlc.arg(delay_seconds)::float8)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateFeedScheduleHints :exec
+UPDATE feeds SET schedule_hints = $2 WHERE id = $1;
+
+-- name: SetFeedFetchInterval :one
+UPDATE feeds
+SET fetch_interval_seconds = $2, adaptive = $3, next_fetch_at = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: RecordFeedSuccess :one
+UPDATE feeds SET consecutive_failures = 0 WHERE id = $1 RETURNING *;
+
+-- name: RecordFeedFailure :one
+UPDATE feeds
+SET consecutive_failures = consecutive_failures + 1,
+    last_error = sqlc.arg(last_error)::text,
+    last_error_at = NOW(),
+    disabled_at = CASE
+        WHEN consecutive_failures + 1 >= sqlc.arg(disable_after)::int THEN NOW()
+        ELSE disabled_at
+    END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetUnhealthyFeeds :many
+SELECT * FROM feeds
+WHERE consecutive_failures > 0 OR disabled_at IS NOT NULL
+ORDER BY disabled_at ASC NULLS LAST, consecutive_failures DESC;
+
+-- name: EnableFeed :one
+UPDATE feeds
+SET disabled_at = NULL, consecutive_failures = 0, next_fetch_at = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateFeedUrl :one
+UPDATE feeds SET url = $2, canonical_url = $3, updated_at = NOW() WHERE id = $1 RETURNING *;
+
+-- name: UpdateFeedMetadata :exec
+UPDATE feeds
+SET title = $2, description = $3, site_url = $4, language = $5, image_url = $6
+WHERE id = $1;
+
+-- name: SetFeedCanonicalUrl :exec
+UPDATE feeds SET url = $2, canonical_url = $3 WHERE id = $1;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1;
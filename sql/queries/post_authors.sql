@@ -0,0 +1,17 @@
+-- name: CreatePostAuthor :exec
+INSERT INTO post_authors (id, created_at, post_id, name)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (post_id, name) DO NOTHING;
+
+-- name: DeletePostAuthors :exec
+DELETE FROM post_authors WHERE post_id = $1;
+
+-- name: GetAuthorsForPosts :many
+SELECT * FROM post_authors
+WHERE post_id = ANY(sqlc.arg(post_ids)::uuid[])
+ORDER BY created_at, name;
@@ -0,0 +1,21 @@
+-- name: CreatePostEnclosure :exec
+INSERT INTO post_enclosures (id, created_at, post_id, url, mime_type, length, duration_seconds)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (post_id, url) DO UPDATE
+SET mime_type = EXCLUDED.mime_type, length = EXCLUDED.length, duration_seconds = EXCLUDED.duration_seconds;
+
+-- name: DeletePostEnclosures :exec
+DELETE FROM post_enclosures WHERE post_id = $1;
+
+-- name: GetEnclosuresForPosts :many
+SELECT * FROM post_enclosures
+WHERE post_id = ANY(sqlc.arg(post_ids)::uuid[])
+ORDER BY created_at, url;
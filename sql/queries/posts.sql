@@ -1,5 +1,5 @@
--- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+-- name: CreatePost :execrows
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content_hash, content, raw_description, raw_content)
 VALUES (
     $1,
     $2,
@@ -8,12 +8,99 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
 )
-RETURNING *;
+ON CONFLICT (feed_id, guid) DO NOTHING;
+
+-- name: GetPostByFeedAndGuid :one
+SELECT * FROM posts WHERE feed_id = $1 AND guid = $2;
+
+-- name: UpdatePost :exec
+UPDATE posts
+SET title = $2, url = $3, description = $4, published_at = $5, content_hash = $6, updated_at = $7, content = $8, raw_description = $9, raw_content = $10, sanitized = true
+WHERE id = $1;
+
+-- name: GetPostsByIDPrefix :many
+SELECT posts.*, feeds.name AS feed_name FROM posts
+INNER JOIN feeds ON feeds.id = posts.feed_id
+WHERE posts.id::text LIKE sqlc.arg(id_prefix)::text || '%'
+ORDER BY posts.id
+LIMIT 2;
 
 -- name: GetPostsForUser :many
 SELECT * FROM posts
 INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
-WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC LIMIT $2;
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(author)::text IS NULL OR EXISTS (
+        SELECT 1 FROM post_authors
+        WHERE post_authors.post_id = posts.id AND post_authors.name ILIKE sqlc.narg(author)::text
+    ))
+    AND (sqlc.narg(category)::text IS NULL OR EXISTS (
+        SELECT 1 FROM post_categories
+        WHERE post_categories.post_id = posts.id AND lower(post_categories.name) = lower(sqlc.narg(category)::text)
+    ))
+ORDER BY posts.published_at DESC NULLS LAST LIMIT sqlc.arg(row_limit);
+
+-- name: GetRecentPostDates :many
+SELECT published_at FROM posts
+WHERE feed_id = $1 AND published_at IS NOT NULL
+ORDER BY published_at DESC LIMIT $2;
+
+-- name: MovePosts :exec
+UPDATE posts SET feed_id = sqlc.arg(to_feed_id)
+WHERE feed_id = sqlc.arg(from_feed_id)
+    AND guid NOT IN (SELECT guid FROM posts WHERE feed_id = sqlc.arg(to_feed_id));
+
+-- name: GetUrlKeyedPosts :many
+SELECT url FROM posts
+WHERE feed_id = sqlc.arg(feed_id)
+    AND guid = url
+    AND url = ANY(sqlc.arg(urls)::text[]);
+
+-- name: RekeyLegacyPost :execrows
+UPDATE posts SET guid = sqlc.arg(guid)
+WHERE id = (
+    SELECT legacy.id FROM posts AS legacy
+    WHERE legacy.feed_id = sqlc.arg(feed_id)
+        AND legacy.guid = legacy.url
+        AND legacy.guid <> sqlc.arg(guid)
+        AND legacy.url = ANY(sqlc.arg(urls)::text[])
+    ORDER BY legacy.created_at
+    LIMIT 1
+)
+AND NOT EXISTS (
+    SELECT 1 FROM posts AS keyed
+    WHERE keyed.feed_id = sqlc.arg(feed_id) AND keyed.guid = sqlc.arg(guid)
+);
+
+-- name: GetPostKeysForFeed :many
+SELECT id, created_at, url, guid FROM posts
+WHERE feed_id = $1
+ORDER BY created_at, id;
+
+-- name: SetPostUrlAndGuid :exec
+UPDATE posts SET url = $2, guid = $3
+WHERE id = $1;
+
+-- name: DeletePost :exec
+DELETE FROM posts WHERE id = $1;
+
+-- name: GetUnsanitizedPosts :many
+SELECT id, description, content FROM posts
+WHERE NOT sanitized
+ORDER BY id
+LIMIT $1;
+
+-- name: SetPostSanitized :exec
+UPDATE posts SET
+    description = sqlc.arg(description),
+    content = sqlc.arg(content),
+    raw_description = COALESCE(raw_description, sqlc.narg(raw_description)),
+    raw_content = COALESCE(raw_content, sqlc.narg(raw_content)),
+    sanitized = true
+WHERE id = sqlc.arg(id);
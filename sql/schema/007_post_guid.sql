@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN guid TEXT;
+
+UPDATE posts SET guid = url;
+
+ALTER TABLE posts
+ALTER COLUMN guid SET NOT NULL,
+DROP CONSTRAINT posts_url_key,
+ADD CONSTRAINT feed_guid_unique UNIQUE(feed_id, guid);
+
+-- +goose Down
+ALTER TABLE posts
+DROP CONSTRAINT feed_guid_unique,
+ADD CONSTRAINT posts_url_key UNIQUE(url);
+
+ALTER TABLE posts
+DROP COLUMN guid;
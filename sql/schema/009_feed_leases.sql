@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN lease_expires_at TIMESTAMP,
+ADD COLUMN leased_by TEXT;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN lease_expires_at,
+DROP COLUMN leased_by;
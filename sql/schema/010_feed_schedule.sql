@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN fetch_interval_seconds INTEGER,
+ADD COLUMN adaptive BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN next_fetch_at TIMESTAMP,
+ADD COLUMN schedule_hints JSONB NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN fetch_interval_seconds,
+DROP COLUMN adaptive,
+DROP COLUMN next_fetch_at,
+DROP COLUMN schedule_hints;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feed_fetches
+ADD COLUMN new_item_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN updated_item_count INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feed_fetches
+DROP COLUMN new_item_count,
+DROP COLUMN updated_item_count;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN canonical_url TEXT UNIQUE;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN canonical_url;
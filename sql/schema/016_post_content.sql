@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN content TEXT;
+
+ALTER TABLE post_revisions
+ADD COLUMN content TEXT;
+
+-- +goose Down
+ALTER TABLE post_revisions
+DROP COLUMN content;
+
+ALTER TABLE posts
+DROP COLUMN content;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN raw_description TEXT,
+ADD COLUMN raw_content TEXT;
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN raw_description,
+DROP COLUMN raw_content;
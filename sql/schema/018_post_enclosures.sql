@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE post_enclosures (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    mime_type TEXT NOT NULL DEFAULT '',
+    length BIGINT,
+    duration_seconds INTEGER,
+    CONSTRAINT post_url_unique UNIQUE(post_id, url)
+);
+
+-- +goose Down
+DROP TABLE post_enclosures;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE post_authors (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    CONSTRAINT post_author_unique UNIQUE(post_id, name)
+);
+
+CREATE TABLE post_categories (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    CONSTRAINT post_category_unique UNIQUE(post_id, name)
+);
+
+CREATE INDEX post_categories_name_idx ON post_categories (lower(name));
+
+-- +goose Down
+DROP TABLE post_categories;
+DROP TABLE post_authors;
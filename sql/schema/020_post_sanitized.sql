@@ -0,0 +1,12 @@
+-- +goose Up
+-- posts stored before html was sanitized at ingest are flagged so agg can
+-- clean them up, rows inserted from now on are sanitized already
+ALTER TABLE posts
+ADD COLUMN sanitized BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE posts
+ALTER COLUMN sanitized SET DEFAULT true;
+
+-- +goose Down
+ALTER TABLE posts
+DROP COLUMN sanitized;
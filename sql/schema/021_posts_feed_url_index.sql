@@ -0,0 +1,7 @@
+-- +goose Up
+-- posts are looked up by their url within a feed when fetching and
+-- canonicalizing, without this every lookup scans all of the feed's posts
+CREATE INDEX posts_feed_url_idx ON posts (feed_id, url);
+
+-- +goose Down
+DROP INDEX posts_feed_url_idx;